@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/url"
+)
+
+// configPath serves the effective configuration of the running operator
+const configPath = "/config"
+
+// secretFlags are never echoed by the config endpoint. Flags are marked where
+// they are defined, through secretStringVar.
+var secretFlags = map[string]bool{}
+
+// secretStringVar defines a string flag whose value the config endpoint redacts
+func secretStringVar(p *string, name, value, usage string) {
+	flag.StringVar(p, name, value, usage)
+	secretFlags[name] = true
+}
+
+// redactedValue returns the value of the flag as echoed by the config
+// endpoint. Secret flags are redacted, as are credentials embedded in URLs.
+func redactedValue(name, value string) string {
+	if value == "" {
+		return value
+	}
+	if secretFlags[name] {
+		return "REDACTED"
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		u.User = url.User("REDACTED")
+		return u.String()
+	}
+	return value
+}
+
+// effectiveConfig is the JSON document served at configPath
+type effectiveConfig struct {
+	// Flags holds the value of every flag, defaults included
+	Flags map[string]string `json:"flags"`
+	// Overridden lists the flags set on the command line
+	Overridden []string `json:"overridden"`
+}
+
+// configHandler returns a read-only handler reporting the parsed flags, with
+// secret values redacted. Callers guard it with an AuthorizingHandler.
+func configHandler(fs *flag.FlagSet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		config := effectiveConfig{Flags: make(map[string]string), Overridden: []string{}}
+		fs.VisitAll(func(f *flag.Flag) {
+			config.Flags[f.Name] = redactedValue(f.Name, f.Value.String())
+		})
+		fs.Visit(func(f *flag.Flag) {
+			config.Overridden = append(config.Overridden, f.Name)
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(config)
+	})
+}
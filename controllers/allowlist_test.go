@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileSkipsNamespacesOutsideAllowlist(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("local", "p-live", "payments"),
+		newNamespace("team-payments", map[string]string{appOwnerLabel: "payments"}),
+		newNamespace("scratch-payments", map[string]string{appOwnerLabel: "payments"}),
+	)
+	r.NamespaceAllowlist = regexp.MustCompile(`^team-`)
+	notAllowed := reconcileResults.WithLabelValues(string(ReasonNotAllowed))
+	before := testutil.ToFloat64(notAllowed)
+
+	wantProject := map[string]string{"team-payments": "p-live", "scratch-payments": ""}
+	for name, want := range wantProject {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			t.Fatalf("get namespace %s: %v", name, err)
+		}
+		// The appOwner label does not override the allowlist
+		if got := namespace.Labels[rancherProjectIDLabel]; got != want {
+			t.Errorf("%s project label = %q, want %q", name, got, want)
+		}
+	}
+
+	if got := testutil.ToFloat64(notAllowed) - before; got != 1 {
+		t.Errorf("NotAllowed results increased by %v, want 1", got)
+	}
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ReasonAmbiguousProject is the event reason listing the candidates of an
+// owner that matches several projects
+const ReasonAmbiguousProject = "AmbiguousProject"
+
+// ambiguousProjectError reports that several projects match a name and the
+// configured policy cannot pick one
+type ambiguousProjectError struct {
+	projectName string
+	// candidates are cluster/project IDs
+	candidates []string
+	detail     string
+}
+
+func newAmbiguousProjectError(projectName string, candidates []*unstructured.Unstructured, detail string) *ambiguousProjectError {
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.GetNamespace()+"/"+candidate.GetName())
+	}
+	return &ambiguousProjectError{projectName: projectName, candidates: ids, detail: detail}
+}
+
+func (e *ambiguousProjectError) Error() string {
+	message := fmt.Sprintf("%d projects match name %q", len(e.candidates), e.projectName)
+	if e.detail != "" {
+		message += " " + e.detail
+	}
+	return message
+}
+
+// reportAmbiguity emits a Warning event enumerating the candidate projects
+// when err is an ambiguous match, so operators can disambiguate
+func (r *NamespaceReconciler) reportAmbiguity(namespace *corev1.Namespace, err error) {
+	var ambiguous *ambiguousProjectError
+	if !errors.As(err, &ambiguous) {
+		return
+	}
+	r.eventf(namespace, corev1.EventTypeWarning, ReasonAmbiguousProject,
+		"Owner matches %d projects named %q: %s. Mark one with %s, pin the cluster with %s or rename the others",
+		len(ambiguous.candidates), ambiguous.projectName, strings.Join(ambiguous.candidates, ", "),
+		preferredProjectAnnotation, viaClusterAnnotation)
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileReportsAmbiguousCandidates(t *testing.T) {
+	preferred := func(project *unstructured.Unstructured) *unstructured.Unstructured {
+		project.SetAnnotations(map[string]string{preferredProjectAnnotation: "true"})
+		return project
+	}
+	hint := fmt.Sprintf("Mark one with %s, pin the cluster with %s or rename the others", preferredProjectAnnotation, viaClusterAnnotation)
+	tests := []struct {
+		name      string
+		policy    AmbiguityPolicy
+		preferred bool
+		wantEvent string
+	}{
+		{name: "fail policy", policy: AmbiguityPolicyFail,
+			wantEvent: `Warning AmbiguousProject Owner matches 2 projects named "payments": c-a/p-one, c-b/p-two. ` + hint},
+		{name: "several preferred", policy: AmbiguityPolicyAnnotation, preferred: true,
+			wantEvent: `Warning AmbiguousProject Owner matches 2 projects named "payments": c-a/p-one, c-b/p-two. ` + hint},
+		{name: "first policy picks one", policy: AmbiguityPolicyFirst},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			one, two := newProject("c-a", "p-one", "payments"), newProject("c-b", "p-two", "payments")
+			if tt.preferred {
+				one, two = preferred(one), preferred(two)
+			}
+			r := newTestReconciler(one, two, newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}))
+			r.AmbiguityPolicy = tt.policy
+			recorder := record.NewFakeRecorder(10)
+			r.Recorder = recorder
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}})
+			if (err != nil) != (tt.wantEvent != "") {
+				t.Fatalf("Reconcile() error = %v, want an error %v", err, tt.wantEvent != "")
+			}
+
+			var ambiguity []string
+			for len(recorder.Events) > 0 {
+				if event := <-recorder.Events; strings.HasPrefix(event, corev1.EventTypeWarning+" "+ReasonAmbiguousProject) {
+					ambiguity = append(ambiguity, event)
+				}
+			}
+			switch {
+			case tt.wantEvent == "" && len(ambiguity) != 0:
+				t.Errorf("unexpected ambiguity events %q", ambiguity)
+			case tt.wantEvent != "" && (len(ambiguity) != 1 || ambiguity[0] != tt.wantEvent):
+				t.Errorf("ambiguity events = %q, want %q", ambiguity, tt.wantEvent)
+			}
+		})
+	}
+}
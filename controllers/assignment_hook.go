@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AssignmentHook is called after a namespace was assigned to a project, for
+// integrations such as notifications or CMDB updates
+type AssignmentHook interface {
+	AfterAssignment(ctx context.Context, namespace *corev1.Namespace, ref *ProjectRef, clusterID string) error
+}
+
+// NoopAssignmentHook is the default hook and does nothing
+type NoopAssignmentHook struct{}
+
+// AfterAssignment implements AssignmentHook
+func (NoopAssignmentHook) AfterAssignment(context.Context, *corev1.Namespace, *ProjectRef, string) error {
+	return nil
+}
+
+// runAssignmentHook invokes the configured hook. Its error is only returned
+// when FailOnHookError is set; otherwise it is logged and reported as an event.
+func (r *NamespaceReconciler) runAssignmentHook(ctx context.Context, namespace *corev1.Namespace, ref *ProjectRef, clusterID string) error {
+	hook := r.AssignmentHook
+	if hook == nil {
+		hook = NoopAssignmentHook{}
+	}
+
+	err := hook.AfterAssignment(ctx, namespace, ref, clusterID)
+	if err == nil {
+		return nil
+	}
+	if r.FailOnHookError {
+		return clusterError(clusterID, "assignment hook failed for namespace "+namespace.Name, err)
+	}
+	log.FromContext(ctx).Error(err, "assignment hook failed", "namespace", namespace.Name, "projectId", ref.ProjectID, "clusterId", clusterID)
+	r.eventf(namespace, corev1.EventTypeWarning, "AssignmentHookFailed", "Post-assignment hook failed: %v", err)
+	return nil
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// recordingHook remembers each assignment it is called for and returns err
+type recordingHook struct {
+	calls []string
+	err   error
+}
+
+func (h *recordingHook) AfterAssignment(_ context.Context, namespace *corev1.Namespace, ref *ProjectRef, clusterID string) error {
+	h.calls = append(h.calls, clusterID+"/"+namespace.Name+"="+ref.ProjectID)
+	return h.err
+}
+
+func TestReconcileRunsAssignmentHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		hookErr   error
+		failOnErr bool
+		wantErr   bool
+		wantEvent bool
+	}{
+		{name: "hook succeeds"},
+		{name: "hook error is reported", hookErr: errors.New("cmdb unavailable"), wantEvent: true},
+		{name: "hook error fails the reconcile", hookErr: errors.New("cmdb unavailable"), failOnErr: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(
+				newProject("local", "p-payments", "payments"),
+				newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+			)
+			hook := &recordingHook{err: tt.hookErr}
+			r.AssignmentHook = hook
+			r.FailOnHookError = tt.failOnErr
+			recorder := record.NewFakeRecorder(10)
+			r.Recorder = recorder
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}
+
+			_, err := r.Reconcile(ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(hook.calls) != 1 || hook.calls[0] != "local/checkout=p-payments" {
+				t.Errorf("hook calls = %q, want one for local/checkout=p-payments", hook.calls)
+			}
+			var hookEvents int
+			for len(recorder.Events) > 0 {
+				if strings.HasPrefix(<-recorder.Events, corev1.EventTypeWarning+" AssignmentHookFailed Post-assignment hook failed: cmdb unavailable") {
+					hookEvents++
+				}
+			}
+			if (hookEvents == 1) != tt.wantEvent || hookEvents > 1 {
+				t.Errorf("AssignmentHookFailed events = %d, want event %v", hookEvents, tt.wantEvent)
+			}
+
+			// The hook only runs for new assignments, not for namespaces already in place
+			hook.err = nil
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("second Reconcile() error = %v", err)
+			}
+			if len(hook.calls) != 1 {
+				t.Errorf("hook called %d times after the namespace was already assigned, want 1", len(hook.calls))
+			}
+		})
+	}
+}
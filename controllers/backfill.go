@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// backfillClusterID sets the missing clusterId label on namespaces that older
+// tooling assigned with only a projectId. The project is not re-resolved; the
+// cluster is taken from the projectId label or, when that holds a bare
+// project name, from Rancher's "cluster:project" annotation. It reports
+// whether the namespace was patched.
+func (r *NamespaceReconciler) backfillClusterID(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, clusterID string) (bool, error) {
+	projectID := namespace.Labels[rancherProjectIDLabel]
+	if projectID == "" || namespace.Labels[rancherClusterIDLabel] != "" {
+		return false, nil
+	}
+
+	projectClusterID := r.extractClusterID(projectID)
+	if projectClusterID == "" {
+		projectClusterID = r.extractClusterID(namespace.Annotations[rancherProjectIDAnnotation])
+	}
+	if projectClusterID == "" {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(namespace.DeepCopy())
+	namespace.Labels[rancherClusterIDLabel] = projectClusterID
+	if err := namespaceClient.Patch(ctx, namespace, patch); err != nil {
+		return false, clusterError(clusterID, "unable to backfill clusterId of namespace "+namespace.Name, err)
+	}
+
+	log.FromContext(ctx).Info("backfilled missing clusterId label", "namespace", namespace.Name, "projectId", projectID, "projectClusterId", projectClusterID)
+	return true, nil
+}
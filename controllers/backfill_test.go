@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileBackfillsMissingClusterID(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		owner       string
+		annotation  string
+		wantCluster string
+	}{
+		{
+			// The owner now resolves to another project, which the backfill must not act on
+			name:        "cluster taken from the project annotation",
+			enabled:     true,
+			owner:       "payments",
+			annotation:  "c-abc:p-legacy",
+			wantCluster: "c-abc",
+		},
+		{name: "no cluster to derive", enabled: true},
+		{name: "backfill disabled", enabled: false, annotation: "c-abc:p-legacy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			namespace := newNamespace("payments", map[string]string{rancherProjectIDLabel: "p-legacy"})
+			if tt.owner != "" {
+				namespace.Labels[appOwnerLabel] = tt.owner
+			}
+			if tt.annotation != "" {
+				namespace.Annotations = map[string]string{rancherProjectIDAnnotation: tt.annotation}
+			}
+			r := newTestReconciler(newProject("local", "p-live", "payments"), namespace)
+			r.BackfillClusterID = tt.enabled
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "payments"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherClusterIDLabel] != tt.wantCluster {
+				t.Errorf("clusterId label = %q, want %q", got.Labels[rancherClusterIDLabel], tt.wantCluster)
+			}
+			if got.Labels[rancherProjectIDLabel] != "p-legacy" {
+				t.Errorf("projectId label = %q, want the existing p-legacy kept", got.Labels[rancherProjectIDLabel])
+			}
+		})
+	}
+}
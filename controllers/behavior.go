@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Behavior selects which resolution logic is used for a namespace
+type Behavior string
+
+const (
+	// BehaviorV1 is the legacy resolution path that matches the raw appOwner
+	// value against Rancher Projects only
+	BehaviorV1 Behavior = "v1"
+	// BehaviorV2 applies owner transforms and consults the external project
+	// resolver before matching Rancher Projects
+	BehaviorV2 Behavior = "v2"
+
+	// behaviorAnnotation opts an individual namespace into a resolution behavior
+	behaviorAnnotation = "rancher-operator.quiknode.io/behavior"
+)
+
+// ParseBehavior validates a behavior name supplied on the command line
+func ParseBehavior(value string) (Behavior, error) {
+	switch behavior := Behavior(value); behavior {
+	case BehaviorV1, BehaviorV2:
+		return behavior, nil
+	default:
+		return "", fmt.Errorf("unknown behavior %q", value)
+	}
+}
+
+// behaviorFor returns the behavior requested by the namespace annotation,
+// falling back to the configured default for missing or unknown values
+func (r *NamespaceReconciler) behaviorFor(namespace *corev1.Namespace) Behavior {
+	if behavior, err := ParseBehavior(namespace.Annotations[behaviorAnnotation]); err == nil {
+		return behavior
+	}
+	if r.DefaultBehavior != "" {
+		return r.DefaultBehavior
+	}
+	return BehaviorV1
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBehaviorForNamespaceAnnotation(t *testing.T) {
+	tests := []struct {
+		annotation string
+		fallback   Behavior
+		want       Behavior
+	}{
+		{annotation: "v2", want: BehaviorV2},
+		{annotation: "v1", fallback: BehaviorV2, want: BehaviorV1},
+		// Unknown gates fall back to the configured default
+		{annotation: "v3", fallback: BehaviorV2, want: BehaviorV2},
+		{annotation: "", want: BehaviorV1},
+	}
+	for _, tt := range tests {
+		r := &NamespaceReconciler{DefaultBehavior: tt.fallback}
+		namespace := newNamespace("payments", nil)
+		namespace.Annotations = map[string]string{behaviorAnnotation: tt.annotation}
+		if got := r.behaviorFor(namespace); got != tt.want {
+			t.Errorf("behaviorFor(%q) with default %q = %q, want %q", tt.annotation, tt.fallback, got, tt.want)
+		}
+	}
+}
+
+func TestBehaviorGateSelectsResolution(t *testing.T) {
+	ctx := context.Background()
+	transforms, err := ParseOwnerTransforms(`[{"type":"stripPrefix","prefix":"team-"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy := newNamespace("legacy", map[string]string{appOwnerLabel: "team-payments"})
+	gated := newNamespace("gated", map[string]string{appOwnerLabel: "team-payments"})
+	gated.Annotations = map[string]string{behaviorAnnotation: string(BehaviorV2)}
+	r := newTestReconciler(newProject("local", "p-live", "payments"))
+	r.OwnerTransforms = transforms
+
+	// Only the namespace opted into v2 has its owner transformed before matching
+	for _, tt := range []struct {
+		namespace   *corev1.Namespace
+		wantProject string
+	}{
+		{namespace: legacy, wantProject: ""},
+		{namespace: gated, wantProject: "p-live"},
+	} {
+		res, err := r.resolveNamespace(ctx, r.Client, tt.namespace, "local")
+		if err != nil {
+			t.Fatalf("resolveNamespace(%s) error = %v", tt.namespace.Name, err)
+		}
+		got := ""
+		if res.Ref != nil {
+			got = res.Ref.ProjectID
+		}
+		if got != tt.wantProject {
+			t.Errorf("resolveNamespace(%s) project = %q, want %q", tt.namespace.Name, got, tt.wantProject)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterSource selects where downstream clusters are discovered from
+type ClusterSource string
+
+const (
+	// ClusterSourceRancher discovers management.cattle.io Clusters and reaches
+	// them through Rancher's cluster proxy
+	ClusterSourceRancher ClusterSource = "rancher"
+	// ClusterSourceCAPI discovers cluster.x-k8s.io Clusters and reaches them
+	// with the kubeconfig secrets Cluster API maintains
+	ClusterSourceCAPI ClusterSource = "capi"
+
+	// capiKubeconfigKey is the key holding the kubeconfig in <cluster>-kubeconfig secrets
+	capiKubeconfigKey = "value"
+)
+
+// ParseClusterSource validates a cluster source supplied on the command line
+func ParseClusterSource(value string) (ClusterSource, error) {
+	switch source := ClusterSource(value); source {
+	case ClusterSourceRancher, ClusterSourceCAPI:
+		return source, nil
+	default:
+		return "", fmt.Errorf("unknown cluster source %q", value)
+	}
+}
+
+// clusterListGVK is the list kind of the clusters discovered from the configured source
+func (r *NamespaceReconciler) clusterListGVK() schema.GroupVersionKind {
+	if r.ClusterSource == ClusterSourceCAPI {
+		return schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "ClusterList"}
+	}
+	return schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ClusterList"}
+}
+
+// discoveredClusterID is the key a discovered cluster's client is held under.
+// Cluster API clusters are namespaced, so their key is namespace/name.
+func (r *NamespaceReconciler) discoveredClusterID(cluster *unstructured.Unstructured) string {
+	if r.ClusterSource == ClusterSourceCAPI {
+		return cluster.GetNamespace() + "/" + cluster.GetName()
+	}
+	return cluster.GetName()
+}
+
+// clusterReady reports whether a discovered cluster can be reached. Rancher
+// clusters report a Ready condition; Cluster API clusters reach the
+// Provisioned phase once their control plane is up.
+func (r *NamespaceReconciler) clusterReady(cluster *unstructured.Unstructured) bool {
+	if r.ClusterSource == ClusterSourceCAPI {
+		phase, _, _ := unstructured.NestedString(cluster.Object, "status", "phase")
+		return phase == "Provisioned"
+	}
+	status, found := clusterConditionStatus(cluster, "Ready")
+	return found && status == "True"
+}
+
+// createCAPIClusterClient creates a client from the <cluster>-kubeconfig
+// secret of the cluster keyed namespace/name
+func (r *NamespaceReconciler) createCAPIClusterClient(ctx context.Context, clusterID string) (client.Client, error) {
+	namespace, clusterName, _ := strings.Cut(clusterID, "/")
+
+	// Read the secret directly so the manager does not start a cluster-wide secret informer
+	secret := &corev1.Secret{}
+	if err := r.Manager.GetAPIReader().Get(ctx, types.NamespacedName{Namespace: namespace, Name: clusterName + "-kubeconfig"}, secret); err != nil {
+		return nil, fmt.Errorf("unable to get kubeconfig secret for cluster %s: %w", clusterName, err)
+	}
+
+	kubeconfig, ok := secret.Data[capiKubeconfigKey]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret for cluster %s has no %q key", clusterName, capiKubeconfigKey)
+	}
+
+	clusterConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse kubeconfig for cluster %s: %w", clusterName, err)
+	}
+
+	if limiter := r.clusterRateLimiter(clusterID); limiter != nil {
+		clusterConfig.RateLimiter = limiter
+	}
+
+	scheme, err := r.downstreamScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterClient, err := client.New(clusterConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client for cluster %s: %w", clusterName, err)
+	}
+	return clusterClient, nil
+}
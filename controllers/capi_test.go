@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: workload
+  cluster:
+    server: https://workload.example.com:6443
+contexts:
+- name: workload
+  context:
+    cluster: workload
+    user: admin
+current-context: workload
+users:
+- name: admin
+  user:
+    token: secret
+`
+
+func newCAPICluster(namespace, name, phase string) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "Cluster"})
+	cluster.SetNamespace(namespace)
+	cluster.SetName(name)
+	_ = unstructured.SetNestedField(cluster.Object, phase, "status", "phase")
+	return cluster
+}
+
+func newKubeconfigSecret(namespace, cluster string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: cluster + "-kubeconfig"},
+		Data:       map[string][]byte{capiKubeconfigKey: []byte(testKubeconfig)},
+	}
+}
+
+func TestCAPIClustersUseSharedRefreshPipeline(t *testing.T) {
+	ctx := context.Background()
+	// Two teams each own a cluster named prod
+	r := newTestReconciler(
+		newCAPICluster("team-a", "prod", "Provisioned"), newKubeconfigSecret("team-a", "prod"),
+		newCAPICluster("team-b", "prod", "Provisioned"), newKubeconfigSecret("team-b", "prod"),
+		newCAPICluster("team-a", "staging", "Provisioning"), newKubeconfigSecret("team-a", "staging"),
+	)
+	r.Manager = &fakeManager{config: &rest.Config{}, reader: r.Client}
+	r.ClusterSource = ClusterSourceCAPI
+
+	r.doRefreshClusterClients(ctx)
+
+	var got []string
+	for clusterID := range r.clusterClients {
+		got = append(got, clusterID)
+	}
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "team-a/prod" || got[1] != "team-b/prod" {
+		t.Fatalf("cluster clients = %v, want the provisioned clusters keyed by namespace/name", got)
+	}
+
+	// The client limit applies to Cluster API clusters like any other
+	r.MaxClusterClients = 1
+	r.doRefreshClusterClients(ctx)
+	if len(r.clusterClients) != 1 {
+		t.Errorf("cluster clients = %d after refresh, want at most MaxClusterClients", len(r.clusterClients))
+	}
+}
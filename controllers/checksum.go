@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// assignmentChecksumAnnotation stores a checksum of the inputs of the last assignment
+const assignmentChecksumAnnotation = "rancher-operator.quiknode.io/assignment-checksum"
+
+// assignmentChecksum hashes the resolved assignment inputs
+func assignmentChecksum(owner, projectID, clusterID string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{owner, projectID, clusterID}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// checksumMatches reports whether the namespace was already assigned with the
+// same inputs and every written project label and annotation still holds
+// them, so it needs no reprocessing. A drifted or removed value fails the
+// match so the drift is corrected.
+func (r *NamespaceReconciler) checksumMatches(namespace *corev1.Namespace, owner, projectID, clusterID string) bool {
+	if !r.AssignmentChecksum || namespace.Annotations[assignmentChecksumAnnotation] != assignmentChecksum(owner, projectID, clusterID) {
+		return false
+	}
+	if r.WriteTargets != WriteAnnotationsOnly {
+		if namespace.Labels[rancherProjectIDLabel] != projectID {
+			return false
+		}
+		if clusterID != "" && namespace.Labels[rancherClusterIDLabel] != clusterID {
+			return false
+		}
+	}
+	if r.WriteTargets != WriteLabelsOnly && namespace.Annotations[rancherProjectIDAnnotation] != projectID {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestChecksumMatches(t *testing.T) {
+	checksum := assignmentChecksum("payments", "p-live", "c-abc")
+	assigned := func(labels, annotations map[string]string) *corev1.Namespace {
+		annotations[assignmentChecksumAnnotation] = checksum
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: labels, Annotations: annotations}}
+	}
+
+	tests := []struct {
+		name      string
+		targets   WriteTargets
+		namespace *corev1.Namespace
+		want      bool
+	}{
+		{
+			name: "fully assigned",
+			namespace: assigned(
+				map[string]string{rancherProjectIDLabel: "p-live", rancherClusterIDLabel: "c-abc"},
+				map[string]string{rancherProjectIDAnnotation: "p-live"}),
+			want: true,
+		},
+		{
+			name: "drifted project label",
+			namespace: assigned(
+				map[string]string{rancherProjectIDLabel: "p-other", rancherClusterIDLabel: "c-abc"},
+				map[string]string{rancherProjectIDAnnotation: "p-live"}),
+		},
+		{
+			name: "removed cluster label",
+			namespace: assigned(
+				map[string]string{rancherProjectIDLabel: "p-live"},
+				map[string]string{rancherProjectIDAnnotation: "p-live"}),
+		},
+		{
+			name: "drifted project annotation",
+			namespace: assigned(
+				map[string]string{rancherProjectIDLabel: "p-live", rancherClusterIDLabel: "c-abc"},
+				map[string]string{rancherProjectIDAnnotation: "p-other"}),
+		},
+		{
+			name:    "labels only ignores the annotation",
+			targets: WriteLabelsOnly,
+			namespace: assigned(
+				map[string]string{rancherProjectIDLabel: "p-live", rancherClusterIDLabel: "c-abc"},
+				map[string]string{}),
+			want: true,
+		},
+		{
+			name:      "annotations only ignores the labels",
+			targets:   WriteAnnotationsOnly,
+			namespace: assigned(map[string]string{}, map[string]string{rancherProjectIDAnnotation: "p-live"}),
+			want:      true,
+		},
+		{
+			name: "stale checksum",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{rancherProjectIDLabel: "p-live", rancherClusterIDLabel: "c-abc"},
+				Annotations: map[string]string{rancherProjectIDAnnotation: "p-live", assignmentChecksumAnnotation: "stale"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &NamespaceReconciler{AssignmentChecksum: true, WriteTargets: tt.targets}
+			if got := r.checksumMatches(tt.namespace, "payments", "p-live", "c-abc"); got != tt.want {
+				t.Errorf("checksumMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clientUsage records when each downstream cluster client was last handed
+// out. Creating a client counts as a use, so new clients are not evicted in
+// favour of clients that have sat idle for longer.
+type clientUsage struct {
+	mutex    sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+func (u *clientUsage) touch(clusterID string, now time.Time) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.lastUsed == nil {
+		u.lastUsed = make(map[string]time.Time)
+	}
+	u.lastUsed[clusterID] = now
+}
+
+// created records the creation of a client that has not been used yet
+func (u *clientUsage) created(clusterID string, now time.Time) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.lastUsed == nil {
+		u.lastUsed = make(map[string]time.Time)
+	}
+	if _, ok := u.lastUsed[clusterID]; !ok {
+		u.lastUsed[clusterID] = now
+	}
+}
+
+// admit returns the clusters that get a client when only slots more clients
+// can be held, most recently used first. Clusters without a client yet come
+// last, in ID order.
+func (u *clientUsage) admit(clusterIDs []string, slots int) []string {
+	if slots < 0 {
+		slots = 0
+	}
+	if len(clusterIDs) <= slots {
+		return clusterIDs
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	admitted := append([]string(nil), clusterIDs...)
+	sort.Slice(admitted, func(i, j int) bool {
+		a, b := u.lastUsed[admitted[i]], u.lastUsed[admitted[j]]
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return admitted[i] < admitted[j]
+	})
+	return admitted[:slots]
+}
+
+// evict removes the least recently used clients until at most max remain and
+// returns the evicted cluster IDs
+func (u *clientUsage) evict(clients map[string]client.Client, max int) []string {
+	if max <= 0 || len(clients) <= max {
+		return nil
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	clusterIDs := make([]string, 0, len(clients))
+	for clusterID := range clients {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Slice(clusterIDs, func(i, j int) bool {
+		a, b := u.lastUsed[clusterIDs[i]], u.lastUsed[clusterIDs[j]]
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return clusterIDs[i] < clusterIDs[j]
+	})
+
+	evicted := clusterIDs[:len(clusterIDs)-max]
+	for _, clusterID := range evicted {
+		delete(clients, clusterID)
+		delete(u.lastUsed, clusterID)
+	}
+	return evicted
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestClientUsageAdmitsRecentlyUsedClusters(t *testing.T) {
+	now := time.Now()
+	usage := clientUsage{}
+	usage.created("c-old", now)
+	usage.created("c-used", now)
+	usage.touch("c-used", now.Add(time.Minute))
+
+	got := usage.admit([]string{"c-new", "c-old", "c-used"}, 2)
+	if want := []string{"c-used", "c-old"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("admit() = %v, want %v", got, want)
+	}
+	if got := usage.admit([]string{"c-new", "c-old"}, 0); len(got) != 0 {
+		t.Errorf("admit() without slots = %v, want none", got)
+	}
+}
+
+func TestClientUsageEvictsByLastUse(t *testing.T) {
+	now := time.Now()
+	usage := clientUsage{}
+	usage.created("c-idle", now)
+	usage.created("c-fresh", now.Add(2*time.Minute))
+	usage.created("c-busy", now)
+	usage.touch("c-busy", now.Add(3*time.Minute))
+
+	clients := map[string]client.Client{"c-idle": nil, "c-fresh": nil, "c-busy": nil}
+	evicted := usage.evict(clients, 2)
+	if want := []string{"c-idle"}; !reflect.DeepEqual(evicted, want) {
+		t.Errorf("evict() = %v, want %v", evicted, want)
+	}
+	if _, ok := clients["c-fresh"]; !ok {
+		t.Error("evict() removed a newly created client")
+	}
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"k8s.io/utils/clock"
+)
+
+// clock returns the configured clock, defaulting to the real one
+func (r *NamespaceReconciler) clock() clock.WithTicker {
+	return clockOrReal(r.Clock)
+}
+
+// clockOrReal returns c, or the real clock when c is nil. Components outside
+// the reconciler use it for their own injectable Clock field.
+func clockOrReal(c clock.WithTicker) clock.WithTicker {
+	if c == nil {
+		return clock.RealClock{}
+	}
+	return c
+}
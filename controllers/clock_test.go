@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestRefreshClusterClientsFollowsInjectedClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	requests := make(chan struct{}, 1)
+	refreshed := make(chan struct{}, 10)
+	r := newTestReconciler()
+	r.Clock = clock
+	r.ClusterRefreshRequests = requests
+	r.ClusterRefreshDebounce = time.Second
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*unstructured.UnstructuredList); ok {
+					refreshed <- struct{}{}
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.refreshClusterClients(ctx)
+	}()
+	expectRefresh := func(want bool, when string) {
+		t.Helper()
+		select {
+		case <-refreshed:
+			if !want {
+				t.Fatalf("unexpected refresh %s", when)
+			}
+		case <-time.After(50 * time.Millisecond):
+			if want {
+				t.Fatalf("no refresh %s", when)
+			}
+		}
+	}
+
+	expectRefresh(true, "on start")
+	for !clock.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Step(clusterRefreshInterval - time.Second)
+	expectRefresh(false, "before the interval elapsed")
+	clock.Step(time.Second)
+	expectRefresh(true, "after the interval elapsed")
+
+	// A requested refresh waits for the debounce on the fake clock
+	requests <- struct{}{}
+	debounced := time.Duration(0)
+	for len(refreshed) == 0 {
+		clock.Step(time.Second)
+		debounced += time.Second
+		time.Sleep(10 * time.Millisecond)
+		if debounced > time.Minute {
+			t.Fatal("no refresh after the debounce elapsed")
+		}
+	}
+	<-refreshed
+	// Let the loop replace its ticker after the refresh returns
+	time.Sleep(10 * time.Millisecond)
+
+	// The interval restarts from the requested refresh
+	clock.Step(clusterRefreshInterval - debounced)
+	expectRefresh(false, "when the original interval elapsed")
+	clock.Step(debounced)
+	expectRefresh(true, "a full interval after the requested refresh")
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestClusterAuthErrorRebuildsClientWithinLimit(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	r.Manager = &fakeManager{config: &rest.Config{Host: "https://rancher.example.com", BearerToken: "token"}}
+	r.MaxClusterClients = 2
+
+	expired := newTestReconciler().Client
+	other := newTestReconciler().Client
+	r.clusterClients = map[string]client.Client{"c-abc": expired, "c-def": other}
+
+	r.handleClusterAuthError(ctx, "c-abc", apierrors.NewUnauthorized("token expired"))
+
+	if len(r.clusterClients) != 2 {
+		t.Fatalf("cluster clients = %d after rebuild, want 2", len(r.clusterClients))
+	}
+	if rebuilt := r.clusterClients["c-abc"]; rebuilt == nil || rebuilt == expired {
+		t.Error("client of c-abc was not rebuilt after a 401")
+	}
+	if r.clusterClients["c-def"] != other {
+		t.Error("client of c-def was replaced by the rebuild of c-abc")
+	}
+
+	// A cluster without a held client is left for the next refresh to admit,
+	// so the rebuild cannot push the pool past MaxClusterClients
+	r.handleClusterAuthError(ctx, "c-ghi", apierrors.NewUnauthorized("token expired"))
+	if _, ok := r.clusterClients["c-ghi"]; ok || len(r.clusterClients) != 2 {
+		t.Errorf("cluster clients = %v, want c-ghi not admitted outside a refresh", r.clusterClients)
+	}
+}
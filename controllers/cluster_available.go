@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// clusterAvailableBuffer bounds the namespaces queued for the controller
+// after clusters gain a client
+const clusterAvailableBuffer = 1024
+
+// newlyAvailableClusters returns the clusters that have a client now but did not before
+func newlyAvailableClusters(previous, current map[string]client.Client) []string {
+	var clusterIDs []string
+	for clusterID := range current {
+		if _, ok := previous[clusterID]; !ok {
+			clusterIDs = append(clusterIDs, clusterID)
+		}
+	}
+	return clusterIDs
+}
+
+// enqueueClusterNamespaces requeues the owned namespaces routed to clusters
+// that just became reachable, since their earlier reconciles had no client.
+// Events are delivered in the background so a controller that has not
+// started yet does not block the refresh.
+func (r *NamespaceReconciler) enqueueClusterNamespaces(ctx context.Context, clusterIDs []string) {
+	if r.clusterAvailable == nil || len(clusterIDs) == 0 {
+		return
+	}
+	logger := log.FromContext(ctx)
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList); err != nil {
+		logger.Error(err, "unable to list namespaces of newly available clusters", "clusters", clusterIDs)
+		return
+	}
+
+	available := make(map[string]bool, len(clusterIDs))
+	for _, clusterID := range clusterIDs {
+		available[clusterID] = true
+	}
+
+	var events []event.GenericEvent
+	for i := range namespaceList.Items {
+		namespace := &namespaceList.Items[i]
+		if owner, _, _ := r.namespaceOwner(ctx, r.Client, namespace, "local"); owner == "" {
+			continue
+		}
+		// Only via-cluster routes depend on refreshed clients. In single-cluster
+		// mode the client is created once at startup and never refreshed.
+		if available[namespace.Annotations[viaClusterAnnotation]] {
+			events = append(events, event.GenericEvent{Object: namespace})
+		}
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	logger.Info("clusters became available, requeueing their namespaces", "clusters", clusterIDs, "namespaces", len(events))
+	go func() {
+		for _, e := range events {
+			select {
+			case r.clusterAvailable <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestEnqueueClusterNamespacesResolvesOwnerThroughFallbacks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	annotated := newNamespace("payments", nil)
+	annotated.Annotations = map[string]string{viaClusterAnnotation: "c-abc", "example.com/team": "payments"}
+	unowned := newNamespace("scratch", nil)
+	unowned.Annotations = map[string]string{viaClusterAnnotation: "c-abc"}
+	r := newTestReconciler(annotated, unowned)
+	r.OwnerAnnotation = "example.com/team"
+	r.clusterAvailable = make(chan event.GenericEvent, 2)
+
+	r.enqueueClusterNamespaces(ctx, []string{"c-abc"})
+
+	select {
+	case e := <-r.clusterAvailable:
+		if e.Object.GetName() != "payments" {
+			t.Errorf("requeued namespace %s, want payments", e.Object.GetName())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("namespace owned through the owner annotation was not requeued")
+	}
+	select {
+	case e := <-r.clusterAvailable:
+		t.Errorf("requeued namespace %s without an owner", e.Object.GetName())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClusterRefresherStopsWithManager(t *testing.T) {
+	r := newTestReconciler()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() { done <- clusterRefresher{reconciler: r}.Start(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cluster refresh kept running after the manager context was cancelled")
+	}
+}
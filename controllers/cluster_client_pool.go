@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// createClusterClients creates a client for each cluster, through Rancher's
+// cluster proxy or from its Cluster API kubeconfig, and adds it to clients. At most ClusterClientWorkers clients
+// are created at once so large fleets do not stall the refresh.
+func (r *NamespaceReconciler) createClusterClients(ctx context.Context, clusterIDs []string, clients map[string]client.Client) {
+	logger := log.FromContext(ctx)
+
+	workers := r.ClusterClientWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, workers)
+
+	for _, clusterID := range clusterIDs {
+		clusterID := clusterID
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			clusterClient, err := r.createClusterClient(ctx, clusterID)
+			if err != nil {
+				logger.Error(err, "unable to create client for cluster", "clusterId", clusterID)
+				clusterClientErrors.WithLabelValues(clusterID).Inc()
+				return
+			}
+
+			mutex.Lock()
+			clients[clusterID] = clusterClient
+			mutex.Unlock()
+			r.clientUsage.created(clusterID, r.clock().Now())
+			logger.Info("created client for cluster", "clusterId", clusterID)
+		}()
+	}
+
+	wg.Wait()
+}
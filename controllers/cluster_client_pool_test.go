@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// slowReader holds every Get for a while and records how many overlap
+type slowReader struct {
+	client.Reader
+
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *slowReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	s.mutex.Lock()
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	s.mutex.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.mutex.Lock()
+	s.inFlight--
+	s.mutex.Unlock()
+	return s.Reader.Get(ctx, key, obj, opts...)
+}
+
+func TestCreateClusterClientsBoundsConcurrency(t *testing.T) {
+	tests := []struct {
+		workers int
+		want    int
+	}{
+		{workers: 0, want: 1},
+		{workers: 3, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d workers", tt.workers), func(t *testing.T) {
+			var clusterIDs []string
+			r := newTestReconciler()
+			for i := 0; i < 8; i++ {
+				clusterID := fmt.Sprintf("fleet/c-%d", i)
+				clusterIDs = append(clusterIDs, clusterID)
+				if err := r.Create(context.Background(), newKubeconfigSecret("fleet", fmt.Sprintf("c-%d", i))); err != nil {
+					t.Fatalf("create secret: %v", err)
+				}
+			}
+			reader := &slowReader{Reader: r.Client}
+			r.Manager = &fakeManager{config: &rest.Config{}, reader: reader}
+			r.ClusterSource = ClusterSourceCAPI
+			r.ClusterClientWorkers = tt.workers
+
+			clients := make(map[string]client.Client)
+			r.createClusterClients(context.Background(), clusterIDs, clients)
+
+			if len(clients) != len(clusterIDs) {
+				t.Errorf("created %d clients, want %d", len(clients), len(clusterIDs))
+			}
+			if reader.maxInFlight != tt.want {
+				t.Errorf("at most %d clients created at once, want %d", reader.maxInFlight, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRefreshKeepsClientOfFlappingClusterDuringGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := newTestReconciler(newRancherCluster("c-abc", "True"))
+	r.Manager = &fakeManager{config: &rest.Config{Host: "https://rancher.example.com", BearerToken: "token"}}
+	r.Clock = clock
+	r.NotReadyGracePeriod = 5 * time.Minute
+
+	setReady := func(status string) {
+		t.Helper()
+		cluster := newRancherCluster("c-abc", status)
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(cluster.GroupVersionKind())
+		if err := r.Get(ctx, client.ObjectKeyFromObject(cluster), current); err != nil {
+			t.Fatalf("get cluster: %v", err)
+		}
+		cluster.SetResourceVersion(current.GetResourceVersion())
+		if err := r.Update(ctx, cluster); err != nil {
+			t.Fatalf("set cluster Ready=%s: %v", status, err)
+		}
+	}
+	refreshAfter := func(wait time.Duration) bool {
+		clock.Step(wait)
+		r.doRefreshClusterClients(ctx)
+		_, ok := r.clusterClients["c-abc"]
+		return ok
+	}
+
+	if !refreshAfter(0) {
+		t.Fatal("ready cluster got no client")
+	}
+	original := r.clusterClients["c-abc"]
+
+	// A brief flap keeps the same client
+	setReady("False")
+	if !refreshAfter(3*time.Minute) || r.clusterClients["c-abc"] != original {
+		t.Error("client dropped or rebuilt while the cluster flapped within the grace period")
+	}
+
+	// Becoming ready again restarts the grace period for the next flap
+	setReady("True")
+	refreshAfter(time.Minute)
+	setReady("False")
+	if !refreshAfter(0) || !refreshAfter(4*time.Minute) {
+		t.Error("client dropped although the cluster has been not ready for less than the grace period")
+	}
+
+	// Staying not ready past the grace period drops the client
+	if refreshAfter(2 * time.Minute) {
+		t.Error("client kept after the cluster stayed not ready beyond the grace period")
+	}
+}
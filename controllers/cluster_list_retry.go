@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultClusterListRetryBackoff is the first delay between cluster List
+// attempts when no backoff is configured. It doubles on every retry.
+const defaultClusterListRetryBackoff = time.Second
+
+// listClusters lists the Rancher clusters, retrying transient failures with an
+// exponential backoff so a single failed List does not leave the cluster
+// clients stale until the next refresh interval
+func (r *NamespaceReconciler) listClusters(ctx context.Context, clusterList *unstructured.UnstructuredList) error {
+	logger := log.FromContext(ctx)
+
+	backoff := r.ClusterListRetryBackoff
+	if backoff <= 0 {
+		backoff = defaultClusterListRetryBackoff
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= r.ClusterListRetries; attempt++ {
+		if attempt > 0 {
+			logger.V(1).Info("retrying cluster list", "attempt", attempt, "backoff", backoff, "error", lastErr.Error())
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-r.clock().After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if lastErr = r.List(ctx, clusterList, r.clusterListOptions()...); lastErr == nil {
+			return nil
+		}
+	}
+
+	if r.ClusterListRetries == 0 {
+		return lastErr
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", r.ClusterListRetries+1, lastErr)
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// failingClusterLists returns a reconciler whose first failures cluster Lists fail
+func failingClusterLists(failures int32, attempts *int32) *NamespaceReconciler {
+	r := newTestReconciler()
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newRancherCluster("c-abc", "True")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if atomic.AddInt32(attempts, 1) <= failures {
+					return apierrors.NewServiceUnavailable("rancher is restarting")
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+	return r
+}
+
+func TestListClustersRetriesWithDoublingBackoff(t *testing.T) {
+	var attempts int32
+	r := failingClusterLists(2, &attempts)
+	clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r.Clock = clock
+	r.ClusterListRetries = 3
+	r.ClusterListRetryBackoff = time.Second
+
+	clusterList := &unstructured.UnstructuredList{}
+	clusterList.SetGroupVersionKind(r.clusterListGVK())
+	done := make(chan error, 1)
+	go func() { done <- r.listClusters(context.Background(), clusterList) }()
+
+	waitForBackoff := func() {
+		t.Helper()
+		for !clock.HasWaiters() {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitForBackoff()
+	clock.Step(time.Second)
+	waitForBackoff()
+	// The second backoff is twice the first, so one second does not release it
+	clock.Step(time.Second)
+	if !clock.HasWaiters() {
+		t.Fatal("second backoff released after one second")
+	}
+	clock.Step(time.Second)
+
+	if err := <-done; err != nil {
+		t.Fatalf("listClusters() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if len(clusterList.Items) != 1 {
+		t.Errorf("listed %d clusters, want 1", len(clusterList.Items))
+	}
+}
+
+func TestListClustersGivesUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		want    string
+	}{
+		{name: "without retries", retries: 0, want: "rancher is restarting"},
+		{name: "retries exhausted", retries: 2, want: "giving up after 3 attempts: rancher is restarting"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			r := failingClusterLists(100, &attempts)
+			r.ClusterListRetries = tt.retries
+			r.ClusterListRetryBackoff = time.Nanosecond
+
+			clusterList := &unstructured.UnstructuredList{}
+			clusterList.SetGroupVersionKind(r.clusterListGVK())
+			err := r.listClusters(context.Background(), clusterList)
+
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("listClusters() error = %v, want %q", err, tt.want)
+			}
+			if !apierrors.IsServiceUnavailable(err) {
+				t.Errorf("listClusters() error %v does not wrap the API error", err)
+			}
+			if got := atomic.LoadInt32(&attempts); got != int32(tt.retries+1) {
+				t.Errorf("attempts = %d, want %d", got, tt.retries+1)
+			}
+		})
+	}
+}
+
+func TestListClustersStopsRetryingOnCancel(t *testing.T) {
+	var attempts int32
+	r := failingClusterLists(100, &attempts)
+	r.ClusterListRetries = 5
+	r.ClusterListRetryBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	clusterList := &unstructured.UnstructuredList{}
+	clusterList.SetGroupVersionKind(r.clusterListGVK())
+	if err := r.listClusters(ctx, clusterList); !errors.Is(err, context.Canceled) {
+		t.Errorf("listClusters() error = %v, want context.Canceled", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestClusterRateLimiterThrottlesRapidCallsPerCluster(t *testing.T) {
+	r := newTestReconciler()
+	r.DownstreamQPS = 0.001
+	r.DownstreamBurst = 2
+
+	limiter := r.clusterRateLimiter("c-busy")
+	for i := 0; i < 2; i++ {
+		if !limiter.TryAccept() {
+			t.Fatalf("call %d throttled within the burst", i+1)
+		}
+	}
+	if limiter.TryAccept() {
+		t.Error("call beyond the burst was not throttled")
+	}
+
+	// A busy cluster must not use up the budget of another one
+	if !r.clusterRateLimiter("c-quiet").TryAccept() {
+		t.Error("other cluster throttled by the busy cluster's calls")
+	}
+
+	// Rebuilding the client must keep the drained bucket
+	r.Manager = &fakeManager{config: &rest.Config{Host: "https://rancher.example.com", BearerToken: "token"}}
+	if _, err := r.createClusterClient(context.Background(), "c-busy"); err != nil {
+		t.Fatalf("createClusterClient() error = %v", err)
+	}
+	if r.clusterRateLimiter("c-busy").TryAccept() {
+		t.Error("client rebuild reset the cluster's token bucket")
+	}
+}
+
+func TestClusterRateLimiterDisabledWithoutQPS(t *testing.T) {
+	r := newTestReconciler()
+	if limiter := r.clusterRateLimiter("c-abc"); limiter != nil {
+		t.Errorf("clusterRateLimiter() = %v, want nil when DownstreamQPS is zero", limiter)
+	}
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// ClusterRefreshWebhookPath is where Rancher or external automation signals
+// that a cluster was added or removed
+const ClusterRefreshWebhookPath = "/refresh-clusters"
+
+// ClusterRefreshWebhook accepts notifications that the cluster list changed
+// and requests an immediate cluster client refresh instead of waiting for the
+// next interval. Requests must carry the configured basic auth credentials.
+type ClusterRefreshWebhook struct {
+	Username string
+	Password string
+
+	requests chan struct{}
+}
+
+// NewClusterRefreshWebhook returns a webhook protected by basic auth
+func NewClusterRefreshWebhook(username, password string) *ClusterRefreshWebhook {
+	return &ClusterRefreshWebhook{
+		Username: username,
+		Password: password,
+		requests: make(chan struct{}, 1),
+	}
+}
+
+// Requests receives a value for each pending refresh request. Notifications
+// arriving while one is pending are merged into it.
+func (w *ClusterRefreshWebhook) Requests() <-chan struct{} {
+	return w.requests
+}
+
+// ServeHTTP implements http.Handler
+func (w *ClusterRefreshWebhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username, password, ok := req.BasicAuth()
+	if !ok ||
+		subtle.ConstantTimeCompare([]byte(username), []byte(w.Username)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(password), []byte(w.Password)) != 1 {
+		rw.Header().Set("WWW-Authenticate", `Basic realm="qn-rancher-operator"`)
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	select {
+	case w.requests <- struct{}{}:
+	default:
+		// A refresh is already pending
+	}
+	rw.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClusterRefreshWebhookAuthentication(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		username string
+		password string
+		noAuth   bool
+		want     int
+	}{
+		{name: "valid credentials", method: http.MethodPost, username: "rancher", password: "s3cret", want: http.StatusAccepted},
+		{name: "wrong password", method: http.MethodPost, username: "rancher", password: "guess", want: http.StatusUnauthorized},
+		{name: "wrong username", method: http.MethodPost, username: "admin", password: "s3cret", want: http.StatusUnauthorized},
+		{name: "no credentials", method: http.MethodPost, noAuth: true, want: http.StatusUnauthorized},
+		{name: "GET", method: http.MethodGet, username: "rancher", password: "s3cret", want: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := NewClusterRefreshWebhook("rancher", "s3cret")
+			req := httptest.NewRequest(tt.method, ClusterRefreshWebhookPath, nil)
+			if !tt.noAuth {
+				req.SetBasicAuth(tt.username, tt.password)
+			}
+			rec := httptest.NewRecorder()
+
+			webhook.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			refreshRequested := len(webhook.Requests()) == 1
+			if wantRefresh := tt.want == http.StatusAccepted; refreshRequested != wantRefresh {
+				t.Errorf("refresh requested = %v, want %v", refreshRequested, wantRefresh)
+			}
+		})
+	}
+}
+
+func TestClusterRefreshWebhookMergesPendingRequests(t *testing.T) {
+	webhook := NewClusterRefreshWebhook("rancher", "s3cret")
+	notify := func() {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, ClusterRefreshWebhookPath, nil)
+		req.SetBasicAuth("rancher", "s3cret")
+		rec := httptest.NewRecorder()
+		webhook.ServeHTTP(rec, req)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+		}
+	}
+
+	// A burst of notifications leaves a single pending refresh
+	for i := 0; i < 3; i++ {
+		notify()
+	}
+	<-webhook.Requests()
+	select {
+	case <-webhook.Requests():
+		t.Fatal("burst of notifications queued more than one refresh")
+	default:
+	}
+
+	// Once the pending refresh is taken, the next notification queues another
+	notify()
+	select {
+	case <-webhook.Requests():
+	default:
+		t.Fatal("notification after a refresh was not queued")
+	}
+}
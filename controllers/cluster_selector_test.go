@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+)
+
+func TestRefreshSkipsClustersExcludedBySelector(t *testing.T) {
+	disabled := newRancherCluster("c-legacy", "True")
+	disabled.SetLabels(map[string]string{"autoassign": "disabled"})
+	r := newTestReconciler(newRancherCluster("c-abc", "True"), disabled)
+	r.Manager = &fakeManager{config: &rest.Config{Host: "https://rancher.example.com", BearerToken: "token"}}
+	selector, err := labels.Parse("autoassign!=disabled")
+	if err != nil {
+		t.Fatalf("parse selector: %v", err)
+	}
+	r.ClusterSelector = selector
+
+	r.doRefreshClusterClients(context.Background())
+
+	if _, ok := r.clusterClients["c-abc"]; !ok {
+		t.Error("unlabeled cluster got no client")
+	}
+	if _, ok := r.clusterClients["c-legacy"]; ok {
+		t.Error("cluster labeled autoassign=disabled got a client")
+	}
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// clusterUpgradeRequeueDelay is how long assignments on an upgrading cluster wait
+const clusterUpgradeRequeueDelay = time.Minute
+
+// clusterConditionStatus returns the status of the Cluster condition of the given type
+func clusterConditionStatus(cluster *unstructured.Unstructured, conditionType string) (string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(cluster.Object, "status", "conditions")
+	for _, cond := range conditions {
+		condMap, ok := cond.(map[string]interface{})
+		if !ok || condMap["type"] != conditionType {
+			continue
+		}
+		status, _ := condMap["status"].(string)
+		return status, true
+	}
+	return "", false
+}
+
+// clusterIsUpgrading reports whether the configured upgrade condition is in
+// progress. Rancher sets it to Unknown while a cluster is being upgraded.
+func (r *NamespaceReconciler) clusterIsUpgrading(cluster *unstructured.Unstructured) bool {
+	if r.UpgradingCondition == "" {
+		return false
+	}
+	status, found := clusterConditionStatus(cluster, r.UpgradingCondition)
+	return found && status == "Unknown"
+}
+
+// setUpgradingClusters records the clusters found upgrading by the last refresh
+func (r *NamespaceReconciler) setUpgradingClusters(clusterIDs map[string]bool) {
+	r.clusterMutex.Lock()
+	defer r.clusterMutex.Unlock()
+
+	r.upgradingClusters = clusterIDs
+}
+
+// clusterUpgrading reports whether the last refresh found the cluster upgrading
+func (r *NamespaceReconciler) clusterUpgrading(clusterID string) bool {
+	r.clusterMutex.RLock()
+	defer r.clusterMutex.RUnlock()
+
+	return r.upgradingClusters[clusterID]
+}
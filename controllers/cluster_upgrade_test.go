@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// newUpdatingCluster returns a ready cluster whose Updated condition has the given status
+func newUpdatingCluster(name, updated string) *unstructured.Unstructured {
+	cluster := newRancherCluster(name, "True")
+	_ = unstructured.SetNestedSlice(cluster.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True"},
+		map[string]interface{}{"type": "Updated", "status": updated},
+	}, "status", "conditions")
+	return cluster
+}
+
+func TestReconcileDefersAssignmentsOnUpgradingCluster(t *testing.T) {
+	tests := []struct {
+		cluster     string
+		condition   string
+		wantRequeue time.Duration
+		wantProject string
+	}{
+		{cluster: "c-upgrading", condition: "Updated", wantRequeue: clusterUpgradeRequeueDelay},
+		{cluster: "c-updated", condition: "Updated", wantProject: "p-payments"},
+		{cluster: "c-upgrading", wantProject: "p-payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cluster+" condition "+tt.condition, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(
+				newUpdatingCluster("c-upgrading", "Unknown"),
+				newUpdatingCluster("c-updated", "True"),
+				newProject(tt.cluster, "p-payments", "payments"),
+				newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+			)
+			r.Manager = &fakeManager{config: &rest.Config{Host: "https://rancher.example.com", BearerToken: "token"}}
+			r.UpgradingCondition = tt.condition
+			r.doRefreshClusterClients(ctx)
+
+			// Route the namespace to the cluster, served by the fake client
+			r.SingleCluster = tt.cluster
+			r.clusterClients[tt.cluster] = r.Client
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if result.RequeueAfter != tt.wantRequeue {
+				t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, tt.wantRequeue)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != tt.wantProject {
+				t.Errorf("project label = %q, want %q", got.Labels[rancherProjectIDLabel], tt.wantProject)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// MatchConfidence describes how reliably the owner was matched to a project
+type MatchConfidence string
+
+const (
+	// ConfidenceExact means the owner equals the project display name
+	ConfidenceExact MatchConfidence = "exact"
+	// ConfidenceHigh means the owner equals an alias, label or annotation value,
+	// or the project came from an external resolver
+	ConfidenceHigh MatchConfidence = "high"
+	// ConfidenceLow means the owner only matched after name normalization
+	ConfidenceLow MatchConfidence = "low"
+
+	// confidenceAnnotation records the match confidence on the namespace
+	confidenceAnnotation = "rancher-operator.quiknode.io/match-confidence"
+)
+
+// matchConfidence grades how the owner matched the selected project
+func (r *NamespaceReconciler) matchConfidence(project *unstructured.Unstructured, owner string) MatchConfidence {
+	if displayName, found, err := unstructured.NestedString(project.Object, "spec", "displayName"); err == nil && found {
+		if strings.EqualFold(displayName, owner) {
+			return ConfidenceExact
+		}
+	}
+
+	var values []string
+	if r.AliasesAnnotation != "" {
+		values = append(values, parseAliases(project.GetAnnotations()[r.AliasesAnnotation])...)
+	}
+	for _, value := range project.GetLabels() {
+		values = append(values, value)
+	}
+	for _, value := range project.GetAnnotations() {
+		values = append(values, value)
+	}
+
+	for _, value := range values {
+		if strings.EqualFold(value, owner) {
+			return ConfidenceHigh
+		}
+	}
+	return ConfidenceLow
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileAnnotatesMatchConfidence(t *testing.T) {
+	ctx := context.Background()
+	project := newProject("local", "p-live", "Payments Team")
+	project.SetAnnotations(map[string]string{DefaultAliasesAnnotation: "billing"})
+	normalizer, err := ParseNameNormalizer("lowercase,alphanumeric")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]MatchConfidence{
+		"exact-owner": ConfidenceExact,
+		"alias-owner": ConfidenceHigh,
+		"fuzzy-owner": ConfidenceLow,
+	}
+	r := newTestReconciler(
+		project,
+		newNamespace("exact-owner", map[string]string{appOwnerLabel: "Payments Team"}),
+		newNamespace("alias-owner", map[string]string{appOwnerLabel: "billing"}),
+		newNamespace("fuzzy-owner", map[string]string{appOwnerLabel: "payments-team"}),
+	)
+	r.NameNormalizer = normalizer
+	r.AliasesAnnotation = DefaultAliasesAnnotation
+	r.AnnotateConfidence = true
+
+	for name, confidence := range want {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			t.Fatalf("get namespace %s: %v", name, err)
+		}
+		if namespace.Labels[rancherProjectIDLabel] != "p-live" {
+			t.Fatalf("%s project label = %q, want p-live", name, namespace.Labels[rancherProjectIDLabel])
+		}
+		if got := namespace.Annotations[confidenceAnnotation]; got != string(confidence) {
+			t.Errorf("%s confidence = %q, want %q", name, got, confidence)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"fmt"
+)
+
+// ConflictPolicy decides what happens when a namespace already carries a
+// project label that differs from the project its owner resolves to
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyPreferOwner overwrites the existing project with the owner's project
+	ConflictPolicyPreferOwner ConflictPolicy = "prefer-owner"
+	// ConflictPolicyKeepExisting leaves the existing project assignment untouched
+	ConflictPolicyKeepExisting ConflictPolicy = "keep-existing"
+	// ConflictPolicyEventAndSkip emits a conflict event and leaves the namespace untouched
+	ConflictPolicyEventAndSkip ConflictPolicy = "event-and-skip"
+)
+
+// ParseConflictPolicy validates a policy name supplied on the command line
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(value); policy {
+	case ConflictPolicyPreferOwner, ConflictPolicyKeepExisting, ConflictPolicyEventAndSkip:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %q", value)
+	}
+}
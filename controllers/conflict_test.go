@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileAppliesConflictPolicy(t *testing.T) {
+	tests := []struct {
+		policy      ConflictPolicy
+		wantProject string
+		wantEvents  []string
+	}{
+		{policy: "", wantProject: "p-owner"},
+		{policy: ConflictPolicyPreferOwner, wantProject: "p-owner"},
+		{policy: ConflictPolicyKeepExisting, wantProject: "p-other"},
+		{
+			policy:      ConflictPolicyEventAndSkip,
+			wantProject: "p-other",
+			wantEvents: []string{
+				corev1.EventTypeWarning + ` ProjectConflict appOwner "payments" resolves to project p-owner but namespace is assigned to p-other`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		name := string(tt.policy)
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(
+				newProject("local", "p-owner", "payments"),
+				newNamespace("payments", map[string]string{appOwnerLabel: "payments", rancherProjectIDLabel: "p-other"}),
+			)
+			r.ConflictPolicy = tt.policy
+			recorder := record.NewFakeRecorder(10)
+			r.Recorder = recorder
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			namespace := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "payments"}, namespace); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got := namespace.Labels[rancherProjectIDLabel]; got != tt.wantProject {
+				t.Errorf("project label = %q, want %q", got, tt.wantProject)
+			}
+
+			var warnings []string
+			for len(recorder.Events) > 0 {
+				if event := <-recorder.Events; strings.HasPrefix(event, corev1.EventTypeWarning) {
+					warnings = append(warnings, event)
+				}
+			}
+			if len(warnings) != len(tt.wantEvents) {
+				t.Fatalf("warning events = %q, want %q", warnings, tt.wantEvents)
+			}
+			for i := range warnings {
+				if warnings[i] != tt.wantEvents[i] {
+					t.Errorf("warning event = %q, want %q", warnings[i], tt.wantEvents[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// resolveCostCenter maps the namespace's cost-center label to a project name
+// through the cost center ConfigMap, whose data keys are cost centers and
+// values project names. It returns the cost center and a nil reference when
+// the label is missing, unmapped or the project is not found.
+func (r *NamespaceReconciler) resolveCostCenter(ctx context.Context, namespace *corev1.Namespace, clusterID string) (string, *ProjectRef, error) {
+	if r.CostCenterLabel == "" || r.CostCenterConfigMap.Name == "" || r.Manager == nil {
+		return "", nil, nil
+	}
+
+	costCenter := strings.TrimSpace(namespace.Labels[r.CostCenterLabel])
+	if costCenter == "" {
+		return "", nil, nil
+	}
+
+	// Read the ConfigMap directly so edits apply without a ConfigMap informer
+	configMap := &corev1.ConfigMap{}
+	if err := r.Manager.GetAPIReader().Get(ctx, r.CostCenterConfigMap, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return costCenter, nil, nil
+		}
+		return costCenter, nil, clusterError("local", "unable to read cost center mapping", err)
+	}
+
+	projectName := strings.TrimSpace(configMap.Data[costCenter])
+	if projectName == "" {
+		return costCenter, nil, nil
+	}
+
+	project, err := r.findProjectByName(withNamespace(ctx, namespace), projectName, clusterID)
+	if err != nil || project == nil {
+		return costCenter, nil, err
+	}
+	ref := r.projectRef(project, projectName)
+	ref.Source = SourceCostCenter
+	return costCenter, ref, nil
+}
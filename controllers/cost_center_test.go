@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileResolvesCostCenterProject(t *testing.T) {
+	const costCenterLabel = "example.io/cost-center"
+	mapping := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "finance", Name: "cost-centers"},
+		Data:       map[string]string{"cc-100": "platform", "cc-200": "ml", "cc-300": " "},
+	}
+	tests := []struct {
+		name       string
+		costCenter string
+		noMapping  bool
+		want       string
+	}{
+		{name: "mapped cost center wins over owner", costCenter: "cc-100", want: "p-platform"},
+		{name: "unmapped cost center", costCenter: "cc-999", want: "p-payments"},
+		{name: "blank mapping", costCenter: "cc-300", want: "p-payments"},
+		{name: "mapped project missing", costCenter: "cc-200", want: "p-payments"},
+		{name: "no label", want: "p-payments"},
+		{name: "ConfigMap missing", costCenter: "cc-100", noMapping: true, want: "p-payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			labels := map[string]string{appOwnerLabel: "payments"}
+			if tt.costCenter != "" {
+				labels[costCenterLabel] = tt.costCenter
+			}
+			objects := []client.Object{
+				newProject("local", "p-platform", "platform"),
+				newProject("local", "p-payments", "payments"),
+				newNamespace("checkout", labels),
+			}
+			if !tt.noMapping {
+				objects = append(objects, mapping.DeepCopy())
+			}
+			r := newTestReconciler(objects...)
+			r.Manager = &fakeManager{reader: r.Client}
+			r.CostCenterLabel = costCenterLabel
+			r.CostCenterConfigMap = types.NamespacedName{Namespace: "finance", Name: "cost-centers"}
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != tt.want {
+				t.Errorf("project label = %q, want %q", got.Labels[rancherProjectIDLabel], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AuthorizingHandler guards a debug endpoint on the metrics server with the
+// caller's Kubernetes credentials. The bearer token is checked with a
+// TokenReview and the request with a SubjectAccessReview on its non-resource
+// URL, so access is granted through RBAC, for example
+// nonResourceURLs: ["/namespaces/*"], verbs: ["get"].
+type AuthorizingHandler struct {
+	// Client issues the reviews against the management cluster
+	Client client.Client
+	Next   http.Handler
+}
+
+// ServeHTTP implements http.Handler
+func (a *AuthorizingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := a.Client.Create(ctx, review); err != nil {
+		log.FromContext(ctx).Error(err, "unable to review debug endpoint token", "path", req.URL.Path)
+		http.Error(w, "unable to authenticate request", http.StatusInternalServerError)
+		return
+	}
+	if !review.Status.Authenticated {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	access := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		UID:    user.UID,
+		Groups: user.Groups,
+		Extra:  extra,
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+			Path: req.URL.Path,
+			Verb: strings.ToLower(req.Method),
+		},
+	}}
+	if err := a.Client.Create(ctx, access); err != nil {
+		log.FromContext(ctx).Error(err, "unable to authorize debug endpoint request", "path", req.URL.Path)
+		http.Error(w, "unable to authorize request", http.StatusInternalServerError)
+		return
+	}
+	if !access.Status.Allowed {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	a.Next.ServeHTTP(w, req)
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Decision outcomes recorded for each terminal reconcile branch
+const (
+	DecisionAssigned = "Assigned"
+	DecisionSkipped  = "Skipped"
+	DecisionError    = "Error"
+)
+
+// ReconcileReason is a stable code describing why a reconcile ended the way it did
+type ReconcileReason string
+
+// Reason codes attached to every terminal reconcile branch
+const (
+	ReasonNamespaceNotFound    ReconcileReason = "NamespaceNotFound"
+	ReasonNamespaceTerminating ReconcileReason = "NamespaceTerminating"
+	ReasonNotAllowed           ReconcileReason = "NotAllowed"
+	ReasonNoOwnerLabel         ReconcileReason = "NoOwnerLabel"
+	ReasonOwnerDisagreement    ReconcileReason = "OwnerDisagreement"
+	ReasonOwnerEmpty           ReconcileReason = "OwnerEmpty"
+	ReasonProjectNotFound      ReconcileReason = "ProjectNotFound"
+	ReasonProjectTerminating   ReconcileReason = "ProjectTerminating"
+	ReasonProjectIneligible    ReconcileReason = "ProjectIneligible"
+	ReasonProjectIDEmpty       ReconcileReason = "ProjectIDEmpty"
+	ReasonProtectedProject     ReconcileReason = "ProtectedProject"
+	ReasonAlreadyAssigned      ReconcileReason = "AlreadyAssigned"
+	ReasonConflict             ReconcileReason = "Conflict"
+	ReasonQuarantined          ReconcileReason = "Quarantined"
+	ReasonNotPersisted         ReconcileReason = "NotPersisted"
+	ReasonUnpatchable          ReconcileReason = "Unpatchable"
+	ReasonDeferred             ReconcileReason = "Deferred"
+	ReasonPlanned              ReconcileReason = "Planned"
+	ReasonStaleCache           ReconcileReason = "StaleCache"
+	ReasonThrottled            ReconcileReason = "Throttled"
+	ReasonClusterUpgrading     ReconcileReason = "ClusterUpgrading"
+	ReasonAssigned             ReconcileReason = "Assigned"
+	ReasonClusterIDBackfilled  ReconcileReason = "ClusterIDBackfilled"
+	ReasonSkipped              ReconcileReason = "Skipped"
+	ReasonError                ReconcileReason = "Error"
+)
+
+// Decision is the structured record of how a single reconcile ended
+type Decision struct {
+	Time      time.Time       `json:"time"`
+	Namespace string          `json:"namespace"`
+	ClusterID string          `json:"clusterId,omitempty"`
+	Owner     string          `json:"owner,omitempty"`
+	ProjectID string          `json:"projectId,omitempty"`
+	Outcome   string          `json:"outcome"`
+	Reason    ReconcileReason `json:"reason"`
+	Message   string          `json:"message,omitempty"`
+	// CorrelationID ties the decision to the reconcile's log lines and metric exemplars
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Duration is how long the reconcile took
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// routedClusterID is the cluster the namespace lives on. ClusterID is
+	// replaced by the project's cluster once a project is resolved.
+	routedClusterID string
+	// resolvedFrom names the source the project was resolved from
+	resolvedFrom string
+	// requeueAfter is the delay before the namespace is reconciled again
+	requeueAfter time.Duration
+}
+
+// namespaceKey identifies the namespace of the decision across clusters
+func (d Decision) namespaceKey() (string, string) {
+	if d.routedClusterID != "" {
+		return d.routedClusterID, d.Namespace
+	}
+	return d.ClusterID, d.Namespace
+}
+
+// routineReason reports whether the reason is a no-op that recurs on every
+// resync, logged only at V(1) so steady state stays quiet
+func routineReason(reason ReconcileReason) bool {
+	switch reason {
+	case ReasonAlreadyAssigned, ReasonNoOwnerLabel, ReasonNotAllowed, ReasonThrottled, ReasonStaleCache,
+		ReasonNamespaceNotFound, ReasonNamespaceTerminating, ReasonQuarantined, ReasonClusterUpgrading, ReasonSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// DecisionSink receives reconcile decisions. Implementations must not block.
+type DecisionSink interface {
+	Record(decision Decision)
+}
+
+// recordDecision finalizes the decision with the reconcile error, logs it as
+// the reconcile's single line, counts it and hands it to the sink
+func (r *NamespaceReconciler) recordDecision(ctx context.Context, decision Decision, err error) {
+	if err != nil {
+		decision.Outcome = DecisionError
+		decision.Reason = ReasonError
+		decision.Message = err.Error()
+	}
+	decision.Time = r.clock().Now()
+
+	logger := log.FromContext(ctx)
+	if routineReason(decision.Reason) {
+		logger = logger.V(1)
+	}
+	keysAndValues := []interface{}{"reason", decision.Reason, "outcome", decision.Outcome,
+		"namespace", decision.Namespace, "appOwner", decision.Owner, "projectId", decision.ProjectID, "clusterId", decision.ClusterID}
+	if decision.resolvedFrom != "" {
+		keysAndValues = append(keysAndValues, "resolvedFrom", decision.resolvedFrom)
+	}
+	if decision.requeueAfter > 0 {
+		keysAndValues = append(keysAndValues, "requeueAfter", decision.requeueAfter)
+	}
+	if decision.Message != "" {
+		keysAndValues = append(keysAndValues, "message", decision.Message)
+	}
+	logger.Info("reconcile finished", keysAndValues...)
+	reconcileResults.WithLabelValues(string(decision.Reason)).Inc()
+	observeReconcileDuration(decision)
+
+	r.namespaceRecords.observe(decision)
+	r.queueStatus(decision)
+	if r.Digest != nil {
+		r.Digest.Observe(decision)
+	}
+	if r.DecisionSink != nil {
+		r.DecisionSink.Record(decision)
+	}
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// logLine is one JSON line written by a funcr logger
+type logLine struct {
+	Level  int    `json:"level"`
+	Msg    string `json:"msg"`
+	Reason string `json:"reason"`
+}
+
+func captureLogs(ctx context.Context) (context.Context, *[]logLine) {
+	var lines []logLine
+	logger := funcr.NewJSON(func(obj string) {
+		var line logLine
+		_ = json.Unmarshal([]byte(obj), &line)
+		lines = append(lines, line)
+	}, funcr.Options{Verbosity: 1})
+	return log.IntoContext(ctx, logger), &lines
+}
+
+func TestReconcileLogsOneLinePerReconcile(t *testing.T) {
+	r := newTestReconciler(newProject("local", "p-live", "payments"), newNamespace("payments", map[string]string{appOwnerLabel: "payments"}))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+
+	for _, want := range []struct {
+		reason ReconcileReason
+		level  int
+	}{
+		// The assignment is logged at Info
+		{reason: ReasonAssigned, level: 0},
+		// Resyncs of the assigned namespace are routine and only logged at V(1)
+		{reason: ReasonAlreadyAssigned, level: 1},
+	} {
+		ctx, lines := captureLogs(context.Background())
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+
+		var finished []logLine
+		infoLines := 0
+		for _, line := range *lines {
+			if line.Msg == "reconcile finished" {
+				finished = append(finished, line)
+			}
+			if line.Level == 0 {
+				infoLines++
+			}
+		}
+		if len(finished) != 1 || finished[0].Reason != string(want.reason) || finished[0].Level != want.level {
+			t.Errorf("reconcile finished lines = %+v, want one with reason %s at level %d", finished, want.reason, want.level)
+		}
+		if wantInfo := 1 - want.level; infoLines != wantInfo {
+			t.Errorf("reconcile logged %d lines at Info, want %d: %+v", infoLines, wantInfo, *lines)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// Defaults for the webhook decision sink
+	defaultWebhookQueueSize  = 1000
+	defaultWebhookMaxRetries = 3
+	defaultWebhookTimeout    = 10 * time.Second
+	webhookRetryBackoff      = time.Second
+)
+
+// WebhookDecisionSink POSTs decisions as JSON to an HTTP endpoint. Decisions are
+// queued and delivered by a background worker so reconciliation never blocks;
+// when the queue is full new decisions are dropped.
+type WebhookDecisionSink struct {
+	URL string
+	// AuthHeader is sent as the Authorization header when set
+	AuthHeader string
+	MaxRetries int
+	Client     *http.Client
+	// Clock times retry backoff and defaults to the real clock
+	Clock clock.WithTicker
+
+	queue chan Decision
+}
+
+// NewWebhookDecisionSink creates a sink with a bounded queue of the given size
+func NewWebhookDecisionSink(url, authHeader string, queueSize int) *WebhookDecisionSink {
+	if queueSize <= 0 {
+		queueSize = defaultWebhookQueueSize
+	}
+	return &WebhookDecisionSink{
+		URL:        url,
+		AuthHeader: authHeader,
+		MaxRetries: defaultWebhookMaxRetries,
+		Client:     &http.Client{Timeout: defaultWebhookTimeout},
+		queue:      make(chan Decision, queueSize),
+	}
+}
+
+// Record enqueues the decision without blocking
+func (s *WebhookDecisionSink) Record(decision Decision) {
+	select {
+	case s.queue <- decision:
+	default:
+		log.Log.WithName("decision-webhook").V(1).Info("decision queue full, dropping decision", "namespace", decision.Namespace)
+	}
+}
+
+// Start delivers queued decisions until the context is cancelled. It implements manager.Runnable.
+func (s *WebhookDecisionSink) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("decision-webhook")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case decision := <-s.queue:
+			if err := s.deliver(ctx, decision); err != nil {
+				logger.Error(err, "unable to deliver decision", "namespace", decision.Namespace)
+			}
+		}
+	}
+}
+
+// deliver POSTs a single decision, retrying with a linear backoff
+func (s *WebhookDecisionSink) deliver(ctx context.Context, decision Decision) error {
+	body, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("unable to encode decision: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-clockOrReal(s.Clock).After(time.Duration(attempt) * webhookRetryBackoff):
+			}
+		}
+
+		if lastErr = s.post(ctx, body); lastErr == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", s.MaxRetries+1, lastErr)
+}
+
+func (s *WebhookDecisionSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.AuthHeader != "" {
+		req.Header.Set("Authorization", s.AuthHeader)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestWebhookDecisionSinkRetriesDelivery(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan Decision, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the configured header", req.Header.Get("Authorization"))
+		}
+		// The first attempt hits an unavailable endpoint
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var decision Decision
+		if err := json.NewDecoder(req.Body).Decode(&decision); err != nil {
+			t.Errorf("body is not a JSON decision: %v", err)
+		}
+		received <- decision
+	}))
+	defer server.Close()
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	sink := NewWebhookDecisionSink(server.URL, "Bearer secret", 1)
+	sink.Clock = fakeClock
+
+	done := make(chan error)
+	go func() {
+		done <- sink.deliver(context.Background(), Decision{Namespace: "payments", Outcome: DecisionAssigned, Reason: ReasonAssigned})
+	}()
+	// Release the backoff before the retry
+	for !fakeClock.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	fakeClock.Step(webhookRetryBackoff)
+
+	if err := <-done; err != nil {
+		t.Fatalf("deliver() error = %v", err)
+	}
+	if decision := <-received; decision.Namespace != "payments" || decision.Reason != ReasonAssigned {
+		t.Errorf("delivered decision = %+v, want the recorded one", decision)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("webhook called %d times, want 2", got)
+	}
+}
+
+func TestWebhookDecisionSinkDropsWhenQueueFull(t *testing.T) {
+	sink := NewWebhookDecisionSink("http://127.0.0.1:0", "", 1)
+
+	// Record never blocks the reconcile, even without a running worker
+	sink.Record(Decision{Namespace: "payments"})
+	sink.Record(Decision{Namespace: "orders"})
+
+	if len(sink.queue) != 1 || (<-sink.queue).Namespace != "payments" {
+		t.Error("a full queue should keep the queued decision and drop the new one")
+	}
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DigestCounts are the per-cluster totals reported in an activity digest
+type DigestCounts struct {
+	Assigned   int `json:"assigned"`
+	Skipped    int `json:"skipped"`
+	Failed     int `json:"failed"`
+	Unresolved int `json:"unresolved"`
+}
+
+// ActivityDigest aggregates reconcile decisions and periodically logs a
+// structured summary of assignment activity per cluster
+type ActivityDigest struct {
+	// Interval between digests, typically 24h
+	Interval time.Duration
+	// Clock defaults to the real clock
+	Clock clock.WithTicker
+
+	mutex  sync.Mutex
+	counts map[string]*DigestCounts
+	since  time.Time
+}
+
+// NewActivityDigest creates a digest reported every interval, timed by clk.
+// A nil clk uses the real clock.
+func NewActivityDigest(interval time.Duration, clk clock.WithTicker) *ActivityDigest {
+	clk = clockOrReal(clk)
+	return &ActivityDigest{
+		Interval: interval,
+		Clock:    clk,
+		counts:   make(map[string]*DigestCounts),
+		since:    clk.Now(),
+	}
+}
+
+// Observe adds a decision to the current digest period
+func (d *ActivityDigest) Observe(decision Decision) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	clusterID := decision.ClusterID
+	if clusterID == "" {
+		clusterID = "local"
+	}
+	counts, ok := d.counts[clusterID]
+	if !ok {
+		counts = &DigestCounts{}
+		d.counts[clusterID] = counts
+	}
+
+	switch {
+	case decision.Outcome == DecisionAssigned:
+		counts.Assigned++
+	case decision.Outcome == DecisionError:
+		counts.Failed++
+	case decision.Reason == ReasonProjectNotFound, decision.Reason == ReasonProjectIneligible:
+		counts.Unresolved++
+	default:
+		counts.Skipped++
+	}
+}
+
+// flush returns the counts for the current period and starts a new one
+func (d *ActivityDigest) flush() (map[string]DigestCounts, time.Time) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	summary := make(map[string]DigestCounts, len(d.counts))
+	for clusterID, counts := range d.counts {
+		summary[clusterID] = *counts
+	}
+	since := d.since
+
+	d.counts = make(map[string]*DigestCounts)
+	d.since = clockOrReal(d.Clock).Now()
+	return summary, since
+}
+
+// Start logs a digest every interval until the context is cancelled. It implements manager.Runnable.
+func (d *ActivityDigest) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("digest")
+
+	clk := clockOrReal(d.Clock)
+	ticker := clk.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			summary, since := d.flush()
+			logger.Info("assignment activity digest", "since", since, "until", clk.Now(), "clusters", summary)
+		}
+	}
+}
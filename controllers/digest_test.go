@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestActivityDigestSummarizesEachPeriod(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	digest := NewActivityDigest(24*time.Hour, clock)
+
+	type digestLine struct {
+		Msg      string                  `json:"msg"`
+		Clusters map[string]DigestCounts `json:"clusters"`
+	}
+	lines := make(chan digestLine, 2)
+	logger := funcr.NewJSON(func(obj string) {
+		var line digestLine
+		_ = json.Unmarshal([]byte(obj), &line)
+		lines <- line
+	}, funcr.Options{})
+	ctx, cancel := context.WithCancel(log.IntoContext(context.Background(), logger))
+	defer cancel()
+
+	for _, decision := range []Decision{
+		{ClusterID: "c-abc", Outcome: DecisionAssigned, Reason: ReasonAssigned},
+		{ClusterID: "c-abc", Outcome: DecisionAssigned, Reason: ReasonAssigned},
+		{ClusterID: "c-abc", Outcome: DecisionError, Reason: ReasonError},
+		{ClusterID: "c-abc", Outcome: DecisionSkipped, Reason: ReasonProjectNotFound},
+		{Outcome: DecisionSkipped, Reason: ReasonNoOwnerLabel},
+	} {
+		digest.Observe(decision)
+	}
+
+	go func() { _ = digest.Start(ctx) }()
+	next := func() digestLine {
+		t.Helper()
+		for !clock.HasWaiters() {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Step(24 * time.Hour)
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("no digest logged after the interval elapsed")
+			return digestLine{}
+		}
+	}
+
+	first := next()
+	want := map[string]DigestCounts{
+		"c-abc": {Assigned: 2, Failed: 1, Unresolved: 1},
+		"local": {Skipped: 1},
+	}
+	if len(first.Clusters) != len(want) {
+		t.Fatalf("digest clusters = %+v, want %+v", first.Clusters, want)
+	}
+	for clusterID, counts := range want {
+		if first.Clusters[clusterID] != counts {
+			t.Errorf("digest for %s = %+v, want %+v", clusterID, first.Clusters[clusterID], counts)
+		}
+	}
+
+	// Each digest only covers its own period
+	digest.Observe(Decision{ClusterID: "c-abc", Outcome: DecisionAssigned, Reason: ReasonAssigned})
+	second := next()
+	if len(second.Clusters) != 1 || second.Clusters["c-abc"] != (DigestCounts{Assigned: 1}) {
+		t.Errorf("second digest clusters = %+v, want only the new assignment", second.Clusters)
+	}
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveNamespaceFromRancherDisplayName(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		labels      map[string]string
+		wantOwner   string
+		wantProject string
+	}{
+		{name: "display name resolves the project", enabled: true, wantOwner: "payments", wantProject: "p-payments"},
+		{name: "owner label takes precedence", enabled: true, labels: map[string]string{appOwnerLabel: "orders"}, wantOwner: "orders", wantProject: "p-orders"},
+		{name: "display name ignored when disabled", enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(
+				newProject("local", "p-payments", "payments"),
+				newProject("local", "p-orders", "orders"),
+			)
+			r.UseDisplayNameOwner = tt.enabled
+			namespace := newNamespace("ns-7f3k2", tt.labels)
+			namespace.Annotations = map[string]string{rancherDisplayNameAnnotation: "payments"}
+
+			res, err := r.resolveNamespace(context.Background(), r.Client, namespace, "local")
+			if err != nil {
+				t.Fatalf("resolveNamespace() error = %v", err)
+			}
+			if res.Owner != tt.wantOwner {
+				t.Errorf("owner = %q, want %q", res.Owner, tt.wantOwner)
+			}
+			gotProject := ""
+			if res.Ref != nil {
+				gotProject = res.Ref.ProjectID
+			}
+			if gotProject != tt.wantProject {
+				t.Errorf("project = %q, want %q", gotProject, tt.wantProject)
+			}
+		})
+	}
+}
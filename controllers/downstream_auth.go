@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+// DefaultDownstreamTokenKey is the Secret key read for the downstream token
+const DefaultDownstreamTokenKey = "token"
+
+// applyDownstreamCredentials makes sure the cluster proxy config can present
+// a token. Rancher's cluster proxy does not accept client certificates, so a
+// certificate based management config needs the token Secret to be set.
+func (r *NamespaceReconciler) applyDownstreamCredentials(ctx context.Context, config *rest.Config, clusterID string) error {
+	if r.DownstreamTokenSecret.Name != "" {
+		token, err := r.downstreamToken(ctx)
+		if err != nil {
+			return clusterError(clusterID, "unable to read downstream token", err)
+		}
+		config.BearerToken = token
+		config.BearerTokenFile = ""
+		// Present only the token so the proxy does not authenticate the certificate instead
+		config.CertData, config.CertFile = nil, ""
+		config.KeyData, config.KeyFile = nil, ""
+		return nil
+	}
+
+	if config.BearerToken == "" && config.BearerTokenFile == "" && config.ExecProvider == nil && config.AuthProvider == nil {
+		return fmt.Errorf("no bearer token for cluster %s: the management config carries no token, exec or auth provider "+
+			"and no downstream token Secret is configured (--downstream-token-secret)", clusterID)
+	}
+	return nil
+}
+
+// downstreamToken reads the token from the configured Secret
+func (r *NamespaceReconciler) downstreamToken(ctx context.Context) (string, error) {
+	key := r.DownstreamTokenKey
+	if key == "" {
+		key = DefaultDownstreamTokenKey
+	}
+
+	// Read the secret directly so the manager does not start a cluster-wide secret informer
+	secret := &corev1.Secret{}
+	if err := r.Manager.GetAPIReader().Get(ctx, r.DownstreamTokenSecret, secret); err != nil {
+		return "", fmt.Errorf("unable to get secret %s: %w", r.DownstreamTokenSecret, err)
+	}
+
+	token := strings.TrimSpace(string(secret.Data[key]))
+	if token == "" {
+		return "", fmt.Errorf("secret %s has no %q key", r.DownstreamTokenSecret, key)
+	}
+	return token, nil
+}
+
+// ParseNamespacedName parses a namespace/name object reference
+func ParseNamespacedName(value string) (types.NamespacedName, error) {
+	if value == "" {
+		return types.NamespacedName{}, nil
+	}
+	namespace, name, found := strings.Cut(value, "/")
+	if !found || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid reference %q, expected namespace/name", value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+func TestApplyDownstreamCredentials(t *testing.T) {
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-system", Name: "downstream-token"},
+		Data:       map[string][]byte{"token": []byte("kubeconfig-u-abc:xyz\n"), "empty": []byte(" ")},
+	}
+	certConfig := func() *rest.Config {
+		return &rest.Config{Host: "https://rancher.example.com", TLSClientConfig: rest.TLSClientConfig{CertData: []byte("cert"), KeyData: []byte("key")}}
+	}
+	tests := []struct {
+		name      string
+		config    *rest.Config
+		secret    types.NamespacedName
+		key       string
+		wantToken string
+		wantErr   string
+	}{
+		{name: "management token is kept", config: &rest.Config{BearerToken: "management"}, wantToken: "management"},
+		{name: "token file is kept", config: &rest.Config{BearerTokenFile: "/var/run/secrets/token"}},
+		{name: "certificate only", config: certConfig(), wantErr: "no bearer token for cluster c-abc"},
+		{name: "token secret replaces certificate", config: certConfig(),
+			secret: types.NamespacedName{Namespace: "cattle-system", Name: "downstream-token"}, wantToken: "kubeconfig-u-abc:xyz"},
+		{name: "empty key", config: certConfig(),
+			secret: types.NamespacedName{Namespace: "cattle-system", Name: "downstream-token"}, key: "empty", wantErr: `has no "empty" key`},
+		{name: "missing secret", config: certConfig(),
+			secret: types.NamespacedName{Namespace: "cattle-system", Name: "missing"}, wantErr: `clusterID=c-abc: unable to get secret cattle-system/missing`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(tokenSecret)
+			r.Manager = &fakeManager{reader: r.Client}
+			r.DownstreamTokenSecret = tt.secret
+			r.DownstreamTokenKey = tt.key
+
+			err := r.applyDownstreamCredentials(context.Background(), tt.config, "c-abc")
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyDownstreamCredentials() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyDownstreamCredentials() error = %v", err)
+			}
+			if tt.config.BearerToken != tt.wantToken {
+				t.Errorf("BearerToken = %q, want %q", tt.config.BearerToken, tt.wantToken)
+			}
+			if tt.secret.Name != "" && (tt.config.CertData != nil || tt.config.KeyData != nil) {
+				t.Error("client certificate still presented alongside the token")
+			}
+		})
+	}
+}
+
+func TestParseNamespacedName(t *testing.T) {
+	if got, err := ParseNamespacedName("cattle-system/downstream-token"); err != nil || got != (types.NamespacedName{Namespace: "cattle-system", Name: "downstream-token"}) {
+		t.Errorf("ParseNamespacedName() = (%v, %v)", got, err)
+	}
+	if got, err := ParseNamespacedName(""); err != nil || got.Name != "" {
+		t.Errorf("ParseNamespacedName(\"\") = (%v, %v), want an empty reference", got, err)
+	}
+	for _, value := range []string{"downstream-token", "/downstream-token", "cattle-system/"} {
+		if _, err := ParseNamespacedName(value); err == nil {
+			t.Errorf("ParseNamespacedName(%q) error = nil, want an error", value)
+		}
+	}
+}
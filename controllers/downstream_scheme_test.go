@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// widget stands in for a CRD that only exists on downstream clusters
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+func (w *widget) DeepCopyObject() runtime.Object {
+	out := *w
+	w.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+func TestDownstreamClientRegistersExtraSchemeBuilders(t *testing.T) {
+	r := newTestReconciler()
+	r.Manager = &fakeManager{config: &rest.Config{Host: "https://rancher.example.com", BearerToken: "token"}}
+	r.DownstreamSchemeBuilders = runtime.NewSchemeBuilder(func(s *runtime.Scheme) error {
+		s.AddKnownTypeWithName(widgetGVK, &widget{})
+		return nil
+	})
+
+	clusterClient, err := r.createClusterClient(context.Background(), "c-abc")
+	if err != nil {
+		t.Fatalf("createClusterClient() error = %v", err)
+	}
+
+	scheme := clusterClient.Scheme()
+	if !scheme.Recognizes(widgetGVK) {
+		t.Errorf("downstream scheme does not recognize %s", widgetGVK)
+	}
+	if !scheme.Recognizes(corev1.SchemeGroupVersion.WithKind("Namespace")) {
+		t.Error("downstream scheme lost the core types")
+	}
+	// The manager scheme must not pick up downstream-only types
+	if r.Scheme.Recognizes(widgetGVK) {
+		t.Error("extra types leaked into the manager scheme")
+	}
+}
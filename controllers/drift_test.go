@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileCountsAndReportsDriftCorrection(t *testing.T) {
+	ctx := context.Background()
+	// Someone moved the namespace to another project by hand
+	r := newTestReconciler(
+		newProject("c-drift", "p-live", "payments"),
+		newNamespace("payments", map[string]string{appOwnerLabel: "payments", rancherProjectIDLabel: "p-manual"}),
+		newNamespace("orders", map[string]string{appOwnerLabel: "payments"}),
+	)
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+	before := testutil.ToFloat64(assignmentsCorrected.WithLabelValues("c-drift"))
+
+	for _, name := range []string{"payments", "orders"} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+	}
+
+	// Only the drifted namespace counts; a first assignment is not a correction
+	if got := testutil.ToFloat64(assignmentsCorrected.WithLabelValues("c-drift")) - before; got != 1 {
+		t.Errorf("assignments corrected increased by %v, want 1", got)
+	}
+	want := corev1.EventTypeNormal + " DriftCorrected Project assignment corrected from p-manual to p-live"
+	if len(recorder.Events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(recorder.Events))
+	}
+	if got := <-recorder.Events; got != want {
+		t.Errorf("event = %q, want %q", got, want)
+	}
+}
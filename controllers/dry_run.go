@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// unpatchableError reports that a namespace rejects project label changes,
+// typically because an admission webhook denies them
+type unpatchableError struct {
+	err error
+}
+
+func (e *unpatchableError) Error() string {
+	return fmt.Sprintf("namespace rejects project assignment: %v", e.err)
+}
+
+func (e *unpatchableError) Unwrap() error {
+	return e.err
+}
+
+// isUnpatchable reports whether err marks the namespace as unpatchable
+func isUnpatchable(err error) bool {
+	var unpatchable *unpatchableError
+	return errors.As(err, &unpatchable)
+}
+
+// errPlanned reports that a patch was computed in plan-only mode and not applied
+var errPlanned = errors.New("plan only, namespace not patched")
+
+// isPlanned reports whether err marks a patch that was only planned
+func isPlanned(err error) bool {
+	return errors.Is(err, errPlanned)
+}
+
+// dryRunPatch submits the patch as a server-side dry run and classifies
+// admission rejections as unpatchable
+func dryRunPatch(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, patch client.Patch) error {
+	err := namespaceClient.Patch(ctx, namespace.DeepCopy(), patch, client.DryRunAll)
+	if err == nil {
+		return nil
+	}
+	if apierrors.IsForbidden(err) || apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+		return &unpatchableError{err: err}
+	}
+	return err
+}
+
+// fieldChange is one label or annotation value a patch would change
+type fieldChange struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// patchDiff lists the labels and annotations a patch would change
+type patchDiff struct {
+	Labels      map[string]fieldChange `json:"labels,omitempty"`
+	Annotations map[string]fieldChange `json:"annotations,omitempty"`
+}
+
+// diffMetadata returns the values that differ between the original and
+// modified maps. Removed keys, such as a cleared pending annotation, are
+// reported with an empty To.
+func diffMetadata(original, modified map[string]string) map[string]fieldChange {
+	changes := make(map[string]fieldChange)
+	for key, value := range modified {
+		if previous, ok := original[key]; !ok || previous != value {
+			changes[key] = fieldChange{From: previous, To: value}
+		}
+	}
+	for key, previous := range original {
+		if _, ok := modified[key]; !ok {
+			changes[key] = fieldChange{From: previous}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// recordDryRunDiff emits an event on the namespace describing the labels and
+// annotations the patch changes, so kubectl describe shows the plan
+func (r *NamespaceReconciler) recordDryRunDiff(original, modified *corev1.Namespace) {
+	diff := patchDiff{
+		Labels:      diffMetadata(original.Labels, modified.Labels),
+		Annotations: diffMetadata(original.Annotations, modified.Annotations),
+	}
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return
+	}
+	r.eventf(modified, corev1.EventTypeNormal, "DryRunDiff", "%s", data)
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestPlanOnlyEmitsDiffWithoutPatching(t *testing.T) {
+	ctx := context.Background()
+
+	pending := newNamespace("payments", map[string]string{appOwnerLabel: "payments"})
+	pending.Annotations = map[string]string{pendingProjectAnnotation: "p-old"}
+	r := newTestReconciler(newProject("local", "p-live", "payments"), pending)
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+	r.PlanOnly = true
+
+	before := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "payments"}, before); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	after := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "payments"}, after); err != nil {
+		t.Fatal(err)
+	}
+	if after.ResourceVersion != before.ResourceVersion {
+		t.Errorf("namespace was patched in plan-only mode: labels %v, annotations %v", after.Labels, after.Annotations)
+	}
+
+	var diff patchDiff
+	select {
+	case event := <-recorder.Events:
+		prefix := corev1.EventTypeNormal + " DryRunDiff "
+		if !strings.HasPrefix(event, prefix) {
+			t.Fatalf("event = %q, want a DryRunDiff event", event)
+		}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(event, prefix)), &diff); err != nil {
+			t.Fatalf("event does not carry a JSON diff: %v", err)
+		}
+	default:
+		t.Fatal("no DryRunDiff event was emitted")
+	}
+
+	if got := diff.Labels[rancherProjectIDLabel]; got != (fieldChange{To: "p-live"}) {
+		t.Errorf("projectId label change = %+v, want it set to p-live", got)
+	}
+	if got := diff.Annotations[rancherProjectIDAnnotation]; got != (fieldChange{To: "p-live"}) {
+		t.Errorf("projectId annotation change = %+v, want it set to p-live", got)
+	}
+	if got := diff.Annotations[pendingProjectAnnotation]; got != (fieldChange{From: "p-old"}) {
+		t.Errorf("pending annotation change = %+v, want it removed", got)
+	}
+}
+
+func TestReconcileSkipsNamespaceWhenDryRunPatchIsRejected(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	r.DryRunPatches = true
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	// An admission webhook denies every label change on the namespace
+	realPatches := 0
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newProject("local", "p-live", "payments"), newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patchOptions := &client.PatchOptions{}
+				patchOptions.ApplyOptions(opts)
+				if len(patchOptions.DryRun) == 0 {
+					realPatches++
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, obj.GetName(),
+					errors.New("labels are immutable"))
+			},
+		}).Build()
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}})
+
+	// The rejection is final, so the namespace is not retried in a loop
+	if err != nil || result != (ctrl.Result{}) {
+		t.Fatalf("Reconcile() = (%+v, %v), want no error and no requeue", result, err)
+	}
+	if realPatches != 0 {
+		t.Errorf("namespace patched %d times after the dry run was rejected", realPatches)
+	}
+	var rejected []string
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.Contains(event, "AssignmentRejected") {
+			rejected = append(rejected, event)
+		}
+	}
+	if len(rejected) != 1 || !strings.HasPrefix(rejected[0], corev1.EventTypeWarning) || !strings.Contains(rejected[0], "p-live") {
+		t.Errorf("AssignmentRejected events = %q, want one warning naming the project", rejected)
+	}
+}
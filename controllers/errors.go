@@ -0,0 +1,11 @@
+package controllers
+
+import (
+	"fmt"
+)
+
+// clusterError wraps an API error with the failed action and the cluster it
+// was issued against, so multi-cluster failures can be traced from the error alone
+func clusterError(clusterID, action string, err error) error {
+	return fmt.Errorf("%s clusterID=%s: %w", action, clusterID, err)
+}
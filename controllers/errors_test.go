@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestErrorsCarryClusterID(t *testing.T) {
+	unavailable := apierrors.NewServiceUnavailable("apiserver is shutting down")
+
+	t.Run("project lookup", func(t *testing.T) {
+		r := newTestReconciler()
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return unavailable
+			},
+		}).Build()
+
+		_, err := r.findProjectByName(context.Background(), "payments", "c-abc")
+		if err == nil || !strings.Contains(err.Error(), "clusterID=c-abc") {
+			t.Errorf("findProjectByName() error = %v, want it to name clusterID=c-abc", err)
+		}
+		if !apierrors.IsServiceUnavailable(err) {
+			t.Errorf("findProjectByName() error = %v, want the API error kept in the chain", err)
+		}
+	})
+
+	t.Run("namespace patch", func(t *testing.T) {
+		r := newTestReconciler(newProject("c-abc", "p-live", "payments"), newNamespace("payments", nil))
+		r.SingleCluster = "c-abc"
+		downstream := fake.NewClientBuilder().WithScheme(r.Scheme).
+			WithObjects(newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					return unavailable
+				},
+			}).Build()
+		r.clusterClients = map[string]client.Client{"c-abc": downstream}
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}})
+		if err == nil || !strings.Contains(err.Error(), "clusterID=c-abc") {
+			t.Errorf("Reconcile() error = %v, want it to name clusterID=c-abc", err)
+		}
+		var status apierrors.APIStatus
+		if !errors.As(err, &status) {
+			t.Errorf("Reconcile() error = %v, want the API error kept in the chain", err)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ParseEventTypes parses a comma separated list of reason=type pairs, for
+// example "ProjectNotFound=Warning,DriftCorrected=Normal"
+func ParseEventTypes(spec string) (map[string]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	eventTypes := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		reason, eventType, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || reason == "" {
+			return nil, fmt.Errorf("invalid event type mapping %q, expected reason=type", pair)
+		}
+		switch eventType {
+		case corev1.EventTypeNormal, corev1.EventTypeWarning:
+		default:
+			return nil, fmt.Errorf("invalid event type %q for reason %s, expected %s or %s",
+				eventType, reason, corev1.EventTypeNormal, corev1.EventTypeWarning)
+		}
+		eventTypes[reason] = eventType
+	}
+	return eventTypes, nil
+}
+
+// eventf emits an event when a recorder is configured. The configured event
+// type mapping overrides eventType for the reason.
+func (r *NamespaceReconciler) eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	if mapped, ok := r.EventTypes[reason]; ok {
+		eventType = mapped
+	}
+	r.Recorder.Eventf(object, eventType, reason, messageFmt, args...)
+}
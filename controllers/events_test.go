@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestParseEventTypes(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{spec: "", want: nil},
+		{spec: "ProjectNotFound=Warning", want: map[string]string{"ProjectNotFound": "Warning"}},
+		{spec: " ProjectNotFound=Warning , DriftCorrected=Normal", want: map[string]string{"ProjectNotFound": "Warning", "DriftCorrected": "Normal"}},
+		{spec: "ProjectNotFound", wantErr: true},
+		{spec: "=Warning", wantErr: true},
+		{spec: "ProjectNotFound=warning", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEventTypes(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEventTypes(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseEventTypes(%q) = %v, want %v", tt.spec, got, tt.want)
+			continue
+		}
+		for reason, eventType := range tt.want {
+			if got[reason] != eventType {
+				t.Errorf("ParseEventTypes(%q)[%s] = %q, want %q", tt.spec, reason, got[reason], eventType)
+			}
+		}
+	}
+}
+
+func TestReconcileEmitsConfiguredEventType(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes map[string]string
+		want       string
+	}{
+		{name: "default", want: "Normal ProjectNotFound No project matches owner \"payments\""},
+		{name: "overridden", eventTypes: map[string]string{"ProjectNotFound": "Warning"},
+			want: "Warning ProjectNotFound No project matches owner \"payments\""},
+		{name: "other reason overridden", eventTypes: map[string]string{"DriftCorrected": "Warning"},
+			want: "Normal ProjectNotFound No project matches owner \"payments\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}))
+			recorder := record.NewFakeRecorder(10)
+			r.Recorder = recorder
+			r.EventTypes = tt.eventTypes
+
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			select {
+			case event := <-recorder.Events:
+				if event != tt.want {
+					t.Errorf("event = %q, want %q", event, tt.want)
+				}
+			default:
+				t.Fatalf("no event recorded, want %q", tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestReconcileDurationCarriesCorrelationExemplar(t *testing.T) {
+	// The correlation ID is only known from the reconcile's log lines
+	var correlationID string
+	logger := funcr.NewJSON(func(obj string) {
+		var line struct {
+			Msg           string `json:"msg"`
+			CorrelationID string `json:"correlationId"`
+		}
+		if err := json.Unmarshal([]byte(obj), &line); err == nil && line.Msg == "reconcile finished" {
+			correlationID = line.CorrelationID
+		}
+	}, funcr.Options{Verbosity: 1})
+	ctx := log.IntoContext(context.Background(), logger)
+
+	r := newTestReconciler(newNamespace("scratch", nil))
+	r.NamespaceAllowlist = regexp.MustCompile(`^team-`)
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "scratch"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if correlationID == "" {
+		t.Fatal("reconcile logged no correlation ID")
+	}
+
+	metric := &dto.Metric{}
+	if err := reconcileDuration.WithLabelValues(string(ReasonNotAllowed)).(prometheus.Metric).Write(metric); err != nil {
+		t.Fatalf("write histogram: %v", err)
+	}
+	for _, bucket := range metric.GetHistogram().GetBucket() {
+		exemplar := bucket.GetExemplar()
+		if exemplar == nil {
+			continue
+		}
+		for _, label := range exemplar.GetLabel() {
+			if label.GetName() == "correlation_id" && label.GetValue() == correlationID {
+				return
+			}
+		}
+	}
+	t.Errorf("no histogram bucket carries an exemplar with correlation_id=%s", correlationID)
+}
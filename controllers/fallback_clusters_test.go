@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFindProjectByNameSearchesFallbackClustersInOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		fallbacks   []string
+		wantCluster string
+	}{
+		{name: "found only in the second fallback", fallbacks: []string{"c-one", "c-two"}, wantCluster: "c-two"},
+		{name: "earlier fallback wins", fallbacks: []string{"c-three", "c-two"}, wantCluster: "c-three"},
+		{name: "no fallback clusters", fallbacks: nil, wantCluster: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// c-one has no matching project; c-two and c-three both do
+			r := newTestReconciler(
+				newProject("c-one", "p-orders", "orders"),
+				newProject("c-two", "p-two", "payments"),
+				newProject("c-three", "p-three", "payments"),
+			)
+			r.FallbackClusters = tt.fallbacks
+
+			project, err := r.findProjectByName(context.Background(), "payments", "c-abc")
+			if err != nil {
+				t.Fatalf("findProjectByName() error = %v", err)
+			}
+			if tt.wantCluster == "" {
+				if project != nil {
+					t.Errorf("findProjectByName() = %s/%s, want no project", project.GetNamespace(), project.GetName())
+				}
+				return
+			}
+			if project == nil || project.GetNamespace() != tt.wantCluster {
+				t.Errorf("findProjectByName() = %v, want the project in %s", project, tt.wantCluster)
+			}
+		})
+	}
+}
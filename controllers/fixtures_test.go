@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestReconciler returns a reconciler backed by a fake management cluster
+// holding the given objects
+func newTestReconciler(objects ...client.Object) *NamespaceReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	return &NamespaceReconciler{
+		Client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme:            scheme,
+		ProtectedProjects: DefaultProtectedProjects,
+	}
+}
+
+// newProject returns a Rancher Project in the cluster's namespace
+func newProject(clusterID, name, displayName string) *unstructured.Unstructured {
+	project := &unstructured.Unstructured{}
+	project.SetGroupVersionKind(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Project"})
+	project.SetNamespace(clusterID)
+	project.SetName(name)
+	_ = unstructured.SetNestedField(project.Object, displayName, "spec", "displayName")
+	return project
+}
+
+// newNamespace returns a namespace with the given labels
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+// newRancherCluster returns a Rancher management Cluster reporting the given
+// Ready condition status
+func newRancherCluster(name, ready string) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Cluster"})
+	cluster.SetName(name)
+	_ = unstructured.SetNestedSlice(cluster.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": ready},
+	}, "status", "conditions")
+	return cluster
+}
+
+// registerProjectKinds makes the Project kinds known to the scheme. The fake
+// client otherwise registers them on every List, which races with
+// concurrent reconciles sharing the scheme.
+func registerProjectKinds(scheme *runtime.Scheme) {
+	gv := schema.GroupVersion{Group: "management.cattle.io", Version: "v3"}
+	scheme.AddKnownTypeWithName(gv.WithKind("Project"), &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(gv.WithKind("ProjectList"), &unstructured.UnstructuredList{})
+}
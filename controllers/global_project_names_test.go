@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestFindProjectByNameGlobalProjectNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		global        bool
+		projects      []client.Object
+		wantProject   string
+		wantAmbiguous bool
+	}{
+		{
+			// Only the namespace's own downstream cluster is searched
+			name:     "disabled ignores other clusters",
+			projects: []client.Object{newProject("c-remote", "p-remote", "payments")},
+		},
+		{
+			name:        "single match on another cluster",
+			global:      true,
+			projects:    []client.Object{newProject("c-remote", "p-remote", "payments")},
+			wantProject: "c-remote/p-remote",
+		},
+		{
+			name:   "matches on several clusters",
+			global: true,
+			projects: []client.Object{
+				newProject("c-app", "p-app", "payments"),
+				newProject("c-remote", "p-remote", "payments"),
+			},
+			wantAmbiguous: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(tt.projects...)
+			r.GlobalProjectNames = tt.global
+
+			project, err := r.findProjectByName(context.Background(), "payments", "c-app")
+
+			var ambiguous *ambiguousProjectError
+			if got := errors.As(err, &ambiguous); got != tt.wantAmbiguous {
+				t.Fatalf("findProjectByName() error = %v, want ambiguous %v", err, tt.wantAmbiguous)
+			}
+			if tt.wantAmbiguous {
+				if len(ambiguous.candidates) != 2 {
+					t.Errorf("ambiguous candidates = %v, want both clusters", ambiguous.candidates)
+				}
+				return
+			}
+			var got string
+			if project != nil {
+				got = project.GetNamespace() + "/" + project.GetName()
+			}
+			if got != tt.wantProject {
+				t.Errorf("findProjectByName() = %q, want %q", got, tt.wantProject)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// hncParentAnnotation is set by the Hierarchical Namespace Controller on subnamespaces
+	hncParentAnnotation = "hnc.x-k8s.io/subnamespace-of"
+)
+
+// parentOwner returns the appOwner label of the namespace's HNC parent, or an
+// empty string when there is no parent or the parent has no owner
+func (r *NamespaceReconciler) parentOwner(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace) (string, error) {
+	parentName := namespace.Annotations[hncParentAnnotation]
+	if parentName == "" {
+		return "", nil
+	}
+
+	parent := &corev1.Namespace{}
+	if err := namespaceClient.Get(ctx, types.NamespacedName{Name: parentName}, parent); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return parent.Labels[appOwnerLabel], nil
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNamespaceOwnerInheritsFromHNCParent(t *testing.T) {
+	subnamespace := func(parent string, labels map[string]string) *corev1.Namespace {
+		ns := newNamespace("payments-feature", labels)
+		ns.Annotations = map[string]string{hncParentAnnotation: parent}
+		return ns
+	}
+
+	tests := []struct {
+		name       string
+		inherit    bool
+		namespace  *corev1.Namespace
+		wantOwner  string
+		wantSource ResolutionSource
+	}{
+		{
+			name:       "child inherits the parent owner",
+			inherit:    true,
+			namespace:  subnamespace("payments", nil),
+			wantOwner:  "payments",
+			wantSource: SourceParent,
+		},
+		{
+			name:       "own label wins over the parent",
+			inherit:    true,
+			namespace:  subnamespace("payments", map[string]string{appOwnerLabel: "orders"}),
+			wantOwner:  "orders",
+			wantSource: SourceLabel,
+		},
+		{
+			name:      "missing parent leaves the owner empty",
+			inherit:   true,
+			namespace: subnamespace("deleted", nil),
+		},
+		{
+			name:      "inheritance disabled",
+			inherit:   false,
+			namespace: subnamespace("payments", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(newNamespace("payments", map[string]string{appOwnerLabel: "payments"}))
+			r.InheritParentOwner = tt.inherit
+
+			owner, source, err := r.namespaceOwner(context.Background(), r.Client, tt.namespace, "local")
+			if err != nil {
+				t.Fatalf("namespaceOwner() error = %v", err)
+			}
+			if owner != tt.wantOwner || source != tt.wantSource {
+				t.Errorf("namespaceOwner() = (%q, %q), want (%q, %q)", owner, source, tt.wantOwner, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// assignmentHistoryAnnotation holds a JSON list of past project assignments
+	assignmentHistoryAnnotation = "rancher-operator.quiknode.io/assignment-history"
+)
+
+// DefaultCarryForwardAnnotations are copied from a predecessor namespace by default
+var DefaultCarryForwardAnnotations = []string{assignmentHistoryAnnotation}
+
+// assignmentHistoryEntry records a single project assignment change
+type assignmentHistoryEntry struct {
+	Time time.Time `json:"time"`
+	From string    `json:"from,omitempty"`
+	To   string    `json:"to"`
+}
+
+// appendAssignmentHistory adds an entry to the encoded history and keeps only
+// the last limit entries. A malformed existing value is replaced.
+func appendAssignmentHistory(existing string, entry assignmentHistoryEntry, limit int) (string, error) {
+	var history []assignmentHistoryEntry
+	if existing != "" {
+		if err := json.Unmarshal([]byte(existing), &history); err != nil {
+			history = nil
+		}
+	}
+
+	history = append(history, entry)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileCapsAssignmentHistory(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakeClock(start)
+	r := newTestReconciler(
+		newProject("local", "p-a", "alpha"),
+		newProject("local", "p-b", "bravo"),
+		newProject("local", "p-c", "charlie"),
+		newNamespace("payments", nil),
+	)
+	r.Clock = clock
+	r.HistoryLimit = 2
+	key := types.NamespacedName{Name: "payments"}
+
+	// Hand the namespace to a new owner three times
+	for _, owner := range []string{"alpha", "bravo", "charlie"} {
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, key, namespace); err != nil {
+			t.Fatalf("get namespace: %v", err)
+		}
+		if namespace.Labels == nil {
+			namespace.Labels = map[string]string{}
+		}
+		namespace.Labels[appOwnerLabel] = owner
+		if err := r.Update(ctx, namespace); err != nil {
+			t.Fatalf("update owner to %s: %v", owner, err)
+		}
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() for owner %s error = %v", owner, err)
+		}
+		clock.Step(time.Hour)
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, key, namespace); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	var history []assignmentHistoryEntry
+	if err := json.Unmarshal([]byte(namespace.Annotations[assignmentHistoryAnnotation]), &history); err != nil {
+		t.Fatalf("decode history %q: %v", namespace.Annotations[assignmentHistoryAnnotation], err)
+	}
+
+	// The first assignment fell off; the last two remain in order
+	want := []assignmentHistoryEntry{
+		{Time: start.Add(time.Hour), From: "p-a", To: "p-b"},
+		{Time: start.Add(2 * time.Hour), From: "p-b", To: "p-c"},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("history = %+v, want %d entries", history, len(want))
+	}
+	for i := range want {
+		if !history[i].Time.Equal(want[i].Time) || history[i].From != want[i].From || history[i].To != want[i].To {
+			t.Errorf("history[%d] = %+v, want %+v", i, history[i], want[i])
+		}
+	}
+}
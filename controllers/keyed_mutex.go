@@ -0,0 +1,48 @@
+package controllers
+
+import "sync"
+
+// keyedMutex serializes work per key while letting different keys proceed in
+// parallel. Entries are removed once no goroutine holds or waits for them.
+//
+// The controller's workqueue already hands a key to one worker at a time, but
+// namespaces are also written outside it: the assignment verifier clears
+// dangling assignments in the background, and the single pass of --once calls
+// Reconcile directly. Both take the namespace's lock so they never interleave
+// with a reconcile of the same namespace.
+type keyedMutex struct {
+	mutex sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock acquires the lock for key and returns the function releasing it
+func (k *keyedMutex) lock(key string) func() {
+	k.mutex.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	entry, ok := k.locks[key]
+	if !ok {
+		entry = &keyedLock{}
+		k.locks[key] = entry
+	}
+	entry.refs++
+	k.mutex.Unlock()
+
+	entry.Lock()
+	return func() {
+		entry.Unlock()
+
+		k.mutex.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mutex.Unlock()
+	}
+}
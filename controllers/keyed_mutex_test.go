@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestConcurrentReconcilesOfSameNamespacePatchOnce(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(newProject("c-abc", "p-live", "payments"))
+	registerProjectKinds(r.Scheme)
+
+	// Each patch holds the write open for a while, so an unserialized second
+	// reconcile would read the namespace before the first patch lands
+	var patches, inFlight, overlapped atomic.Int32
+	downstream := fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patches.Add(1)
+				if inFlight.Add(1) > 1 {
+					overlapped.Store(1)
+				}
+				defer inFlight.Add(-1)
+				time.Sleep(50 * time.Millisecond)
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+	r.SingleCluster = "c-abc"
+	r.clusterClients = map[string]client.Client{"c-abc": downstream}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	if overlapped.Load() != 0 {
+		t.Error("patches of the same namespace ran concurrently")
+	}
+	// The second reconcile sees the first one's assignment and leaves the namespace alone
+	if got := patches.Load(); got != 1 {
+		t.Errorf("namespace patched %d times, want 1", got)
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := downstream.Get(ctx, types.NamespacedName{Name: "payments"}, namespace); err != nil {
+		t.Fatal(err)
+	}
+	if namespace.Labels[rancherProjectIDLabel] != "p-live" {
+		t.Errorf("namespace projectId = %q, want %q", namespace.Labels[rancherProjectIDLabel], "p-live")
+	}
+}
+
+func TestVerifyFixKeepsAssignmentChangedByReconcile(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(newNamespace("orders", map[string]string{appOwnerLabel: "orders", rancherProjectIDLabel: "c-abc:p-live"}))
+
+	// The verifier listed the namespace while it pointed at a deleted project,
+	// then a reconcile reassigned it before the fix ran
+	err := r.clearDanglingAssignment(ctx, r.Client, "local", "orders", "c-abc:p-gone")
+	if err == nil {
+		t.Fatal("clearDanglingAssignment() succeeded, want an error for the changed assignment")
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "orders"}, namespace); err != nil {
+		t.Fatal(err)
+	}
+	if namespace.Labels[rancherProjectIDLabel] != "c-abc:p-live" {
+		t.Errorf("namespace projectId = %q, want the reconciled assignment kept", namespace.Labels[rancherProjectIDLabel])
+	}
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Bounds for the requeue delay applied while the management API is slow
+	minLatencyBackoff = 5 * time.Second
+	maxLatencyBackoff = 5 * time.Minute
+
+	// latencySmoothing is the weight of each new sample in the rolling average
+	latencySmoothing = 0.2
+)
+
+// latencyTracker keeps an exponentially weighted rolling average of project List latency
+type latencyTracker struct {
+	mutex    sync.Mutex
+	average  time.Duration
+	observed time.Time
+}
+
+func (t *latencyTracker) observe(latency time.Duration, now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.average == 0 {
+		t.average = latency
+	} else {
+		t.average = time.Duration((1-latencySmoothing)*float64(t.average) + latencySmoothing*float64(latency))
+	}
+	t.observed = now
+}
+
+// backoff returns how long reconciles should be delayed because the rolling
+// latency exceeds threshold. The delay scales with how far the threshold is
+// exceeded and expires once it has elapsed since the last sample, so a later
+// reconcile can measure the latency again.
+func (t *latencyTracker) backoff(threshold time.Duration, now time.Time) time.Duration {
+	if threshold <= 0 {
+		return 0
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.average <= threshold {
+		return 0
+	}
+
+	delay := time.Duration(float64(minLatencyBackoff) * float64(t.average) / float64(threshold))
+	if delay > maxLatencyBackoff {
+		delay = maxLatencyBackoff
+	}
+
+	remaining := delay - now.Sub(t.observed)
+	if remaining <= 0 {
+		return 0
+	}
+	return remaining
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileBacksOffWhenProjectListIsSlow(t *testing.T) {
+	tests := []struct {
+		latency      time.Duration
+		wantRequeue  time.Duration
+		wantAssigned bool
+	}{
+		{latency: 100 * time.Millisecond, wantRequeue: 0, wantAssigned: true},
+		{latency: time.Second, wantRequeue: 10 * time.Second},
+		{latency: 2 * time.Second, wantRequeue: 20 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.latency.String(), func(t *testing.T) {
+			ctx := context.Background()
+			clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			r := newTestReconciler()
+			r.Clock = clock
+			r.LatencyThreshold = 500 * time.Millisecond
+			// The management API takes the given latency to list projects
+			r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+				WithObjects(
+					newProject("local", "p-live", "payments"),
+					newNamespace("first", map[string]string{appOwnerLabel: "payments"}),
+					newNamespace("second", map[string]string{appOwnerLabel: "payments"}),
+				).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						if u, ok := list.(*unstructured.UnstructuredList); ok && u.GetKind() == "ProjectList" {
+							clock.Step(tt.latency)
+						}
+						return c.List(ctx, list, opts...)
+					},
+				}).Build()
+
+			// The first reconcile measures the latency
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "first"}}); err != nil {
+				t.Fatalf("Reconcile(first) error = %v", err)
+			}
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "second"}})
+			if err != nil {
+				t.Fatalf("Reconcile(second) error = %v", err)
+			}
+			if result.RequeueAfter != tt.wantRequeue {
+				t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, tt.wantRequeue)
+			}
+			namespace := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "second"}, namespace); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if assigned := namespace.Labels[rancherProjectIDLabel] == "p-live"; assigned != tt.wantAssigned {
+				t.Errorf("second namespace assigned = %v, want %v", assigned, tt.wantAssigned)
+			}
+		})
+	}
+}
+
+func TestLatencyBackoffExpiresAfterDelay(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var tracker latencyTracker
+	tracker.observe(2*time.Minute, start)
+
+	// Far above the threshold the delay is capped
+	if got := tracker.backoff(time.Second, start); got != maxLatencyBackoff {
+		t.Errorf("backoff() = %s, want the %s cap", got, maxLatencyBackoff)
+	}
+	if got := tracker.backoff(time.Second, start.Add(4*time.Minute)); got != time.Minute {
+		t.Errorf("backoff() after 4m = %s, want the remaining 1m", got)
+	}
+	// Once the delay has passed a reconcile may measure the latency again
+	if got := tracker.backoff(time.Second, start.Add(maxLatencyBackoff)); got != 0 {
+		t.Errorf("backoff() after the delay = %s, want 0", got)
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Lint problems reported for owner labels
+const (
+	LintProblemPattern    = "OwnerDoesNotMatchPattern"
+	LintProblemUnresolved = "OwnerDoesNotResolve"
+	LintProblemError      = "ResolutionError"
+)
+
+// LintFinding describes a namespace whose owner label needs attention
+type LintFinding struct {
+	Namespace string `json:"namespace"`
+	Owner     string `json:"owner"`
+	Problem   string `json:"problem"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// Lint reports namespaces whose appOwner label does not match pattern or does
+// not resolve to any project. It never modifies the cluster.
+func (r *NamespaceReconciler) Lint(ctx context.Context, pattern *regexp.Regexp) ([]LintFinding, error) {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		return nil, clusterError("local", "unable to list namespaces", err)
+	}
+
+	var findings []LintFinding
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		owner := namespace.Labels[appOwnerLabel]
+		if owner == "" {
+			continue
+		}
+
+		if pattern != nil && !pattern.MatchString(owner) {
+			findings = append(findings, LintFinding{
+				Namespace: namespace.Name,
+				Owner:     owner,
+				Problem:   LintProblemPattern,
+				Detail:    fmt.Sprintf("does not match %s", pattern),
+			})
+			continue
+		}
+
+		behavior := r.behaviorFor(namespace)
+		ref, err := r.resolveProject(ctx, namespace, r.normalizeOwner(owner, behavior), "local", behavior)
+		switch {
+		case err != nil:
+			findings = append(findings, LintFinding{Namespace: namespace.Name, Owner: owner, Problem: LintProblemError, Detail: err.Error()})
+		case ref == nil:
+			findings = append(findings, LintFinding{Namespace: namespace.Name, Owner: owner, Problem: LintProblemUnresolved})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Namespace < findings[j].Namespace
+	})
+	return findings, nil
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLintReportsNonConformingOwners(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("local", "p-live", "payments"),
+		newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+		newNamespace("legacy", map[string]string{appOwnerLabel: "Payments_Team"}),
+		newNamespace("orphan", map[string]string{appOwnerLabel: "search"}),
+		newNamespace("kube-public", nil),
+	)
+
+	findings, err := r.Lint(ctx, regexp.MustCompile(`^[a-z][a-z0-9-]*$`))
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+
+	want := []LintFinding{
+		{Namespace: "legacy", Owner: "Payments_Team", Problem: LintProblemPattern},
+		{Namespace: "orphan", Owner: "search", Problem: LintProblemUnresolved},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("findings = %+v, want %+v", findings, want)
+	}
+	for i := range want {
+		got := findings[i]
+		if got.Namespace != want[i].Namespace || got.Owner != want[i].Owner || got.Problem != want[i].Problem {
+			t.Errorf("finding %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// Linting never assigns anything
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		t.Fatalf("list namespaces: %v", err)
+	}
+	for _, namespace := range namespaces.Items {
+		if projectID, ok := namespace.Labels[rancherProjectIDLabel]; ok {
+			t.Errorf("namespace %s was assigned to %s by lint", namespace.Name, projectID)
+		}
+	}
+}
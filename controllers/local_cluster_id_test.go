@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileWritesCanonicalLocalClusterID(t *testing.T) {
+	tests := []struct {
+		localClusterID string
+		want           string
+	}{
+		{localClusterID: "", want: "local"},
+		{localClusterID: "c-m-7x2kq", want: "c-m-7x2kq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(
+				newProject("local", "p-live", "payments"),
+				newNamespace("payments", map[string]string{appOwnerLabel: "payments"}),
+			)
+			r.LocalClusterID = tt.localClusterID
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			namespace := &corev1.Namespace{}
+			if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got := namespace.Labels[rancherClusterIDLabel]; got != tt.want {
+				t.Errorf("clusterId label = %q, want %q", got, tt.want)
+			}
+
+			// The canonical ID is recognized as assigned on the next reconcile
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("second Reconcile() error = %v", err)
+			}
+			again := &corev1.Namespace{}
+			if err := r.Get(ctx, req.NamespacedName, again); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if again.ResourceVersion != namespace.ResourceVersion {
+				t.Errorf("second reconcile patched the namespace again (resourceVersion %s -> %s)", namespace.ResourceVersion, again.ResourceVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a daily time range in UTC during which namespace
+// patches are allowed. Windows may wrap past midnight.
+type MaintenanceWindow struct {
+	// Start and End are offsets from midnight UTC
+	Start time.Duration
+	End   time.Duration
+}
+
+// windowDeferredError reports that a patch was deferred until the next window
+type windowDeferredError struct {
+	wait time.Duration
+}
+
+func (e *windowDeferredError) Error() string {
+	return fmt.Sprintf("outside maintenance window, deferred for %s", e.wait)
+}
+
+// windowDeferral returns the wait until the next window if err deferred a patch
+func windowDeferral(err error) (time.Duration, bool) {
+	var deferred *windowDeferredError
+	if errors.As(err, &deferred) {
+		return deferred.wait, true
+	}
+	return 0, false
+}
+
+// ParseMaintenanceWindows parses a comma separated list of HH:MM-HH:MM ranges in UTC
+func ParseMaintenanceWindows(spec string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		start, end, ok := strings.Cut(item, "-")
+		if !ok {
+			return nil, fmt.Errorf("maintenance window %q must be HH:MM-HH:MM", item)
+		}
+		startOffset, err := parseClockOffset(start)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %q: %w", item, err)
+		}
+		endOffset, err := parseClockOffset(end)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %q: %w", item, err)
+		}
+		if startOffset == endOffset {
+			return nil, fmt.Errorf("maintenance window %q is empty", item)
+		}
+		windows = append(windows, MaintenanceWindow{Start: startOffset, End: endOffset})
+	}
+	return windows, nil
+}
+
+func parseClockOffset(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// contains reports whether the offset from midnight falls inside the window
+func (w MaintenanceWindow) contains(offset time.Duration) bool {
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	// Window wraps past midnight
+	return offset >= w.Start || offset < w.End
+}
+
+// untilNextWindow returns zero when now is inside one of the windows,
+// otherwise the time until the nearest window opens
+func untilNextWindow(now time.Time, windows []MaintenanceWindow) time.Duration {
+	if len(windows) == 0 {
+		return 0
+	}
+
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	offset := now.Sub(midnight)
+
+	var wait time.Duration
+	for i, window := range windows {
+		if window.contains(offset) {
+			return 0
+		}
+		until := window.Start - offset
+		if until <= 0 {
+			until += 24 * time.Hour
+		}
+		if i == 0 || until < wait {
+			wait = until
+		}
+	}
+	return wait
+}
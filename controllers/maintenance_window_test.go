@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileDefersPatchUntilMaintenanceWindow(t *testing.T) {
+	ctx := context.Background()
+	clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC))
+	r := newTestReconciler(
+		newProject("local", "p-live", "payments"),
+		newNamespace("payments", map[string]string{appOwnerLabel: "payments"}),
+	)
+	r.Clock = clock
+	windows, err := ParseMaintenanceWindows("13:00-14:00")
+	if err != nil {
+		t.Fatalf("ParseMaintenanceWindows() error = %v", err)
+	}
+	r.MaintenanceWindows = windows
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+	projectLabel := func() string {
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+			t.Fatalf("get namespace: %v", err)
+		}
+		return namespace.Labels[rancherProjectIDLabel]
+	}
+
+	// Outside the window the patch waits for the window to open
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile() outside window error = %v", err)
+	}
+	if result.RequeueAfter != 30*time.Minute {
+		t.Errorf("RequeueAfter = %s, want 30m until the window opens", result.RequeueAfter)
+	}
+	if got := projectLabel(); got != "" {
+		t.Errorf("project label = %q outside the window, want it unset", got)
+	}
+
+	// The requeue lands inside the window and applies the assignment
+	clock.Step(result.RequeueAfter)
+	result, err = r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile() inside window error = %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("RequeueAfter = %s inside the window, want none", result.RequeueAfter)
+	}
+	if got := projectLabel(); got != "p-live" {
+		t.Errorf("project label = %q inside the window, want p-live", got)
+	}
+}
+
+func TestUntilNextWindow(t *testing.T) {
+	windows := []MaintenanceWindow{
+		{Start: 2 * time.Hour, End: 4 * time.Hour},
+		// Wraps past midnight
+		{Start: 22 * time.Hour, End: 1 * time.Hour},
+	}
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Duration
+		want time.Duration
+	}{
+		{at: 3 * time.Hour, want: 0},
+		{at: 23 * time.Hour, want: 0},
+		{at: 30 * time.Minute, want: 0},
+		{at: 1 * time.Hour, want: time.Hour},
+		{at: 12 * time.Hour, want: 10 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := untilNextWindow(day.Add(tt.at), windows); got != tt.want {
+			t.Errorf("untilNextWindow(%s) = %s, want %s", tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// clusterClientErrors counts failures to build a client for a downstream cluster
+	clusterClientErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rancher_operator_cluster_client_errors_total",
+			Help: "Total number of failures creating a client for a downstream cluster.",
+		},
+		[]string{"cluster_id"},
+	)
+
+	// assignmentsCorrected counts overwrites of an existing, different project label
+	assignmentsCorrected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rancher_operator_assignments_corrected_total",
+			Help: "Total number of namespaces whose drifted project assignment was corrected.",
+		},
+		[]string{"cluster_id"},
+	)
+
+	// reconcileResults counts terminal reconcile branches by reason code
+	reconcileResults = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rancher_operator_reconcile_results_total",
+			Help: "Total number of reconciles by terminal reason code.",
+		},
+		[]string{"reason"},
+	)
+
+	// reconcilePanics counts panics recovered in Reconcile
+	reconcilePanics = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rancher_operator_reconcile_panics_total",
+			Help: "Total number of panics recovered during reconciliation.",
+		},
+	)
+
+	// reconcileDuration measures reconcile latency by terminal reason code.
+	// Observations carry the correlation ID as an exemplar.
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rancher_operator_reconcile_duration_seconds",
+			Help:    "Duration of namespace reconciles by terminal reason code.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"reason"},
+	)
+
+	// healthyClusterClients tracks the number of downstream cluster clients held after the last refresh
+	healthyClusterClients = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rancher_operator_healthy_cluster_clients",
+			Help: "Number of downstream cluster clients successfully created during the last refresh.",
+		},
+	)
+)
+
+func init() {
+	// Register custom metrics with the controller-runtime registry so they are
+	// served on the manager's metrics endpoint
+	metrics.Registry.MustRegister(
+		clusterClientErrors,
+		assignmentsCorrected,
+		reconcileResults,
+		reconcilePanics,
+		reconcileDuration,
+		healthyClusterClients,
+	)
+}
+
+// observeReconcileDuration records the reconcile duration, attaching the
+// correlation ID as an exemplar so dashboards can link to the matching logs
+func observeReconcileDuration(decision Decision) {
+	observer := reconcileDuration.WithLabelValues(string(decision.Reason))
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && decision.CorrelationID != "" {
+		exemplarObserver.ObserveWithExemplar(decision.Duration.Seconds(), prometheus.Labels{"correlation_id": decision.CorrelationID})
+		return
+	}
+	observer.Observe(decision.Duration.Seconds())
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// fakeManager serves a fixed REST config and API reader. Other manager
+// methods are not implemented.
+type fakeManager struct {
+	manager.Manager
+	config *rest.Config
+	reader client.Reader
+}
+
+func (m *fakeManager) GetConfig() *rest.Config {
+	return m.config
+}
+
+func (m *fakeManager) GetAPIReader() client.Reader {
+	return m.reader
+}
+
+func TestClusterClientErrorsCountsCreationFailures(t *testing.T) {
+	// Without a token the downstream client cannot authenticate to the cluster proxy
+	r := newTestReconciler()
+	r.Manager = &fakeManager{config: &rest.Config{Host: "https://rancher.example.com"}}
+
+	before := testutil.ToFloat64(clusterClientErrors.WithLabelValues("c-broken"))
+	clients := make(map[string]client.Client)
+	r.createClusterClients(context.Background(), []string{"c-broken"}, clients)
+
+	if len(clients) != 0 {
+		t.Errorf("createClusterClients() created %d clients, want none", len(clients))
+	}
+	if got := testutil.ToFloat64(clusterClientErrors.WithLabelValues("c-broken")) - before; got != 1 {
+		t.Errorf("rancher_operator_cluster_client_errors_total increased by %v, want 1", got)
+	}
+}
+
+func TestHealthyClusterClientsTracksRefresh(t *testing.T) {
+	r := newTestReconciler()
+	r.setClusterClients(context.Background(), map[string]client.Client{"c-abc": r.Client, "c-def": r.Client})
+
+	if got := testutil.ToFloat64(healthyClusterClients); got != 2 {
+		t.Errorf("healthy cluster clients gauge = %v, want 2", got)
+	}
+}
+
+func TestReconcileRecoversFromResolverPanic(t *testing.T) {
+	r := newTestReconciler()
+	// A malformed Project makes the resolver panic while listing projects
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				panic("malformed project")
+			},
+		}).Build()
+	before := testutil.ToFloat64(reconcilePanics)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}})
+
+	if err == nil {
+		t.Fatal("Reconcile() error = nil, want the recovered panic")
+	}
+	if result != (ctrl.Result{}) {
+		t.Errorf("Reconcile() result = %+v, want empty so the error requeues", result)
+	}
+	if got := testutil.ToFloat64(reconcilePanics) - before; got != 1 {
+		t.Errorf("reconcile panics increased by %v, want 1", got)
+	}
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Supported name normalization steps
+const (
+	NormalizeLowercase    = "lowercase"
+	NormalizeAlphanumeric = "alphanumeric"
+	NormalizeDiacritics   = "diacritics"
+)
+
+// NameNormalizer normalizes both the owner value and project names before
+// they are compared, so "Payments Team!" can match "payments-team"
+type NameNormalizer struct {
+	// Lowercase folds names to lower case
+	Lowercase bool
+	// Alphanumeric drops every rune that is not a letter or digit
+	Alphanumeric bool
+	// Diacritics applies NFKD normalization and strips combining marks, so
+	// "México" compares equal to "Mexico"
+	Diacritics bool
+}
+
+// ParseNameNormalizer parses a comma separated list of normalization steps
+func ParseNameNormalizer(spec string) (*NameNormalizer, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	normalizer := &NameNormalizer{}
+	for _, step := range strings.Split(spec, ",") {
+		switch strings.TrimSpace(step) {
+		case NormalizeLowercase:
+			normalizer.Lowercase = true
+		case NormalizeAlphanumeric:
+			normalizer.Alphanumeric = true
+		case NormalizeDiacritics:
+			normalizer.Diacritics = true
+		default:
+			return nil, fmt.Errorf("unknown name normalization %q", step)
+		}
+	}
+	return normalizer, nil
+}
+
+// Normalize applies the configured steps to the name
+func (n *NameNormalizer) Normalize(name string) string {
+	if n == nil {
+		return name
+	}
+	if n.Diacritics {
+		name = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, norm.NFKD.String(name))
+	}
+	if n.Alphanumeric {
+		name = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, name)
+	}
+	if n.Lowercase {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// namesMatch compares a project name with the owner value case-insensitively,
+// falling back to the configured normalizer
+func (r *NamespaceReconciler) namesMatch(name, owner string) bool {
+	if strings.EqualFold(name, owner) {
+		return true
+	}
+	if r.NameNormalizer == nil {
+		return false
+	}
+	normalized := r.NameNormalizer.Normalize(owner)
+	return normalized != "" && r.NameNormalizer.Normalize(name) == normalized
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import "testing"
+
+func TestProjectMatchesNormalizedNames(t *testing.T) {
+	normalizer, err := ParseNameNormalizer("lowercase,alphanumeric")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		displayName string
+		owner       string
+		want        bool
+	}{
+		{displayName: "Payments Team!", owner: "payments-team", want: true},
+		{displayName: "payments_team", owner: "Payments Team", want: true},
+		{displayName: "  Payments\tTeam ", owner: "PAYMENTS.TEAM", want: true},
+		{displayName: "Payments Team 2", owner: "payments-team", want: false},
+		{displayName: "Payments", owner: "payments-team", want: false},
+		// Nothing is left to compare once punctuation is dropped
+		{displayName: "!!!", owner: "---", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.displayName+"/"+tt.owner, func(t *testing.T) {
+			r := &NamespaceReconciler{NameNormalizer: normalizer}
+			project := newProject("c-abc", "p-live", tt.displayName)
+			if got := r.projectMatches(project, tt.owner); got != tt.want {
+				t.Errorf("projectMatches(%q, %q) = %v, want %v", tt.displayName, tt.owner, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectMatchesWithoutNormalizer(t *testing.T) {
+	r := &NamespaceReconciler{}
+	if !r.projectMatches(newProject("c-abc", "p-live", "Payments"), "payments") {
+		t.Error("projectMatches() should compare names case-insensitively")
+	}
+	if r.projectMatches(newProject("c-abc", "p-live", "Payments Team!"), "payments-team") {
+		t.Error("projectMatches() should not normalize punctuation without a normalizer")
+	}
+}
+
+func TestProjectMatchesDiacritics(t *testing.T) {
+	normalizer, err := ParseNameNormalizer("diacritics,lowercase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &NamespaceReconciler{NameNormalizer: normalizer}
+
+	if !r.projectMatches(newProject("c-abc", "p-live", "México"), "mexico") {
+		t.Error(`projectMatches("México", "mexico") = false, want true`)
+	}
+	// NFKD also folds compatibility forms such as ligatures
+	if !r.projectMatches(newProject("c-abc", "p-live", "Ofﬁce Zürich"), "office zurich") {
+		t.Error(`projectMatches("Ofﬁce Zürich", "office zurich") = false, want true`)
+	}
+	if r.projectMatches(newProject("c-abc", "p-live", "México"), "mexicali") {
+		t.Error(`projectMatches("México", "mexicali") = true, want false`)
+	}
+}
+
+func TestProjectMatchesNormalizesOnlyNames(t *testing.T) {
+	normalizer, err := ParseNameNormalizer("lowercase,alphanumeric")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &NamespaceReconciler{NameNormalizer: normalizer, AliasesAnnotation: DefaultAliasesAnnotation}
+
+	aliased := newProject("c-abc", "p-live", "Checkout")
+	aliased.SetAnnotations(map[string]string{DefaultAliasesAnnotation: "Payments Team!"})
+	if !r.projectMatches(aliased, "payments-team") {
+		t.Error("projectMatches() should normalize aliases")
+	}
+
+	// A label or annotation value only normalizing to the owner is not a name
+	labeled := newProject("c-abc", "p-live", "Checkout")
+	labeled.SetLabels(map[string]string{"cost-center": "payments.team"})
+	labeled.SetAnnotations(map[string]string{"description": "Payments Team"})
+	if r.projectMatches(labeled, "payments-team") {
+		t.Error("projectMatches() should not normalize arbitrary label and annotation values")
+	}
+	if !r.projectMatches(labeled, "PAYMENTS.TEAM") {
+		t.Error("projectMatches() should still match label values case-insensitively")
+	}
+}
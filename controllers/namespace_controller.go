@@ -2,22 +2,36 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -25,6 +39,8 @@ const (
 	rancherProjectIDLabel      = "field.cattle.io/projectId"
 	rancherClusterIDLabel      = "field.cattle.io/clusterId"
 	rancherProjectIDAnnotation = "field.cattle.io/projectId"
+	// Rancher annotation holding the human display name of a namespace
+	rancherDisplayNameAnnotation = "field.cattle.io/displayName"
 
 	// Label we use to determine project assignment
 	appOwnerLabel = "appOwner"
@@ -39,34 +55,412 @@ const (
 
 	// Cluster refresh interval
 	clusterRefreshInterval = 5 * time.Minute
+
+	// viaClusterAnnotation pins the cluster client used for a namespace, for
+	// debugging and special-case routing
+	viaClusterAnnotation = "rancher-operator.quiknode.io/via-cluster"
+
+	// DefaultAliasesAnnotation is the default project annotation listing alternate names
+	DefaultAliasesAnnotation = "rancher-operator.quiknode.io/aliases"
+
+	// DefaultResolutionAnnotation is the suggested key of the machine-readable resolution annotation
+	DefaultResolutionAnnotation = "rancher-operator.quiknode.io/resolution"
 )
 
+// AmbiguityPolicy controls how findProjectByName behaves when more than one
+// project matches the owner value
+type AmbiguityPolicy string
+
+const (
+	// AmbiguityPolicyFail returns an error when several projects match
+	AmbiguityPolicyFail AmbiguityPolicy = "fail"
+	// AmbiguityPolicyFirst picks the match with the lowest project ID
+	AmbiguityPolicyFirst AmbiguityPolicy = "first"
+	// AmbiguityPolicyAnnotation picks the single match carrying the preferred project annotation
+	AmbiguityPolicyAnnotation AmbiguityPolicy = "annotation"
+	// AmbiguityPolicyWeighted spreads namespaces across the matches by their
+	// weight annotation, keeping the choice stable per namespace
+	AmbiguityPolicyWeighted AmbiguityPolicy = "weighted"
+	// AmbiguityPolicyRecency picks the most recently created match
+	AmbiguityPolicyRecency AmbiguityPolicy = "recency"
+
+	// preferredProjectAnnotation marks a project as preferred when several projects match
+	preferredProjectAnnotation = "rancher-operator.quiknode.io/preferred"
+)
+
+// ParseAmbiguityPolicy validates a policy name supplied on the command line
+func ParseAmbiguityPolicy(value string) (AmbiguityPolicy, error) {
+	switch policy := AmbiguityPolicy(value); policy {
+	case AmbiguityPolicyFail, AmbiguityPolicyFirst, AmbiguityPolicyAnnotation, AmbiguityPolicyWeighted, AmbiguityPolicyRecency:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown ambiguity policy %q", value)
+	}
+}
+
+// projectResolution is the machine-readable summary of a project assignment
+// written to the resolution annotation so admission policies can validate it
+type projectResolution struct {
+	Owner     string `json:"owner"`
+	ProjectID string `json:"projectId"`
+	ClusterID string `json:"clusterId"`
+}
+
 // NamespaceReconciler reconciles a Namespace object
 type NamespaceReconciler struct {
 	client.Client
-	Scheme             *runtime.Scheme
-	Manager            manager.Manager
-	clusterClients     map[string]client.Client
-	clusterMutex       sync.RWMutex
+	Scheme  *runtime.Scheme
+	Manager manager.Manager
+	// ResolutionAnnotation is the annotation key used to record the resolution
+	// summary as JSON. Leave empty to disable writing the annotation.
+	ResolutionAnnotation string
+	// AmbiguityPolicy decides which project wins when several match. Defaults to first.
+	AmbiguityPolicy AmbiguityPolicy
+	// OwnerTransforms are applied in order to the appOwner value before resolution
+	OwnerTransforms []OwnerTransform
+	// DecisionSink optionally receives a record of every reconcile outcome
+	DecisionSink DecisionSink
+	// ProjectResolver is consulted before the Rancher Project lookup when set
+	ProjectResolver ProjectResolver
+	// ProjectLister replaces the Project objects on the management cluster as
+	// the source of projects when set. The project cache is then unused.
+	ProjectLister ProjectLister
+	// DefaultBehavior is used for namespaces without a behavior annotation. Defaults to v1.
+	DefaultBehavior Behavior
+	// Recorder emits events on namespaces. Events are skipped when nil.
+	Recorder record.EventRecorder
+	// DownstreamSchemeBuilders register additional types, such as CRDs, on the
+	// scheme used by downstream cluster clients
+	DownstreamSchemeBuilders runtime.SchemeBuilder
+	// NameNormalizer is applied to owner values and project display names and
+	// aliases when matching
+	NameNormalizer *NameNormalizer
+	// ProtectedProjects lists project names that namespaces are never assigned
+	// to unless they carry the allow-protected-project annotation
+	ProtectedProjects []string
+	// InheritParentOwner reads the appOwner label of the HNC parent namespace
+	// when a namespace has no appOwner label of its own
+	InheritParentOwner bool
+	// DownstreamQPS and DownstreamBurst configure a token bucket per downstream
+	// cluster. Zero QPS leaves the client-go defaults in place.
+	DownstreamQPS   float32
+	DownstreamBurst int
+	// OwnerAnnotation is read when the appOwner label is missing. OwnerTemplate,
+	// when set, renders the annotation value into the owner.
+	OwnerAnnotation string
+	OwnerTemplate   *template.Template
+	// HistoryLimit caps the assignment history annotation. Zero disables it.
+	HistoryLimit int
+	// ConflictPolicy applies when the namespace is assigned to a different project
+	// than its owner resolves to. Defaults to prefer-owner.
+	ConflictPolicy ConflictPolicy
+	// ProjectCacheTTL enables caching the Rancher Project list for the given
+	// duration. Zero lists projects on every reconcile.
+	ProjectCacheTTL time.Duration
+	// NamespaceAllowlist, when set, must match a namespace name for it to be assigned
+	NamespaceAllowlist *regexp.Regexp
+	// Digest aggregates decisions into a periodic activity summary when set
+	Digest *ActivityDigest
+	// AliasesAnnotation is the project annotation listing alternate names that
+	// owners may match. Leave empty to disable alias matching.
+	AliasesAnnotation string
+	// SingleCluster pins the operator to one cluster ID, disabling cluster
+	// discovery and scoping project resolution to that cluster
+	SingleCluster string
+	// DryRunPatches validates every patch with a server-side dry run first and
+	// skips namespaces whose admission rejects the change
+	DryRunPatches bool
+	// PlanOnly emits the change each namespace would receive as a DryRunDiff
+	// event and never patches, so an assignment run can be reviewed first
+	PlanOnly bool
+	// MaintenanceWindows restrict namespace patches to the given daily UTC
+	// windows. Patches are allowed at any time when empty.
+	MaintenanceWindows []MaintenanceWindow
+	// FallbackClusters are searched in order when the owner's project is not
+	// found in the namespace's own cluster
+	FallbackClusters []string
+	// AnnotateConfidence stamps how reliably the owner matched the project
+	AnnotateConfidence bool
+	// ClusterSource selects how downstream clusters are discovered. Defaults to rancher.
+	ClusterSource ClusterSource
+	// LatencyThreshold delays reconciles while the rolling project List latency
+	// exceeds it. Zero disables adaptive backoff.
+	LatencyThreshold time.Duration
+	// OwnerConfigMap and OwnerConfigMapKey name a ConfigMap in each namespace
+	// whose key holds the owner when the appOwner label is missing
+	OwnerConfigMap    string
+	OwnerConfigMapKey string
+	// LocalClusterID is the canonical ID written to the clusterId label in place
+	// of "local" for projects on the management cluster
+	LocalClusterID string
+	// StatusConfigMap, when set, names a ConfigMap in StatusNamespace that holds
+	// the last reconcile state of every namespace as conditions. Decisions are
+	// batched and written every few seconds.
+	StatusConfigMap string
+	StatusNamespace string
+	// NotReadyGracePeriod is how long a cluster may report not ready before its
+	// client is dropped. Zero drops the client on the first not-ready refresh.
+	NotReadyGracePeriod time.Duration
+	// UseDisplayNameOwner uses the Rancher displayName annotation as the owner
+	// when no other owner source is present
+	UseDisplayNameOwner bool
+	// WriteTargets selects whether labels, annotations or both are written.
+	// Defaults to both.
+	WriteTargets WriteTargets
+	// CarryForwardAnnotations lists annotations copied from the namespace named in
+	// the predecessor annotation when a namespace replaces another one
+	CarryForwardAnnotations []string
+	// ProjectCacheRebuildInterval rebuilds the project cache in full on a fixed
+	// schedule. Zero disables periodic rebuilds.
+	ProjectCacheRebuildInterval time.Duration
+	// ClusterSelector limits cluster discovery to clusters whose labels match,
+	// e.g. autoassign!=disabled
+	ClusterSelector labels.Selector
+	// PriorityClassLabel is the namespace label stamped with the PriorityClass
+	// named in the project's priority class annotation. Leave empty to disable.
+	PriorityClassLabel string
+	// ResolutionProfiles, when set, replace the single appOwner lookup with
+	// an ordered list of owner labels, match modes and project filters
+	ResolutionProfiles []ResolutionProfile
+	// ProjectRequiredLabel restricts resolution to projects matching the
+	// selector; projects that later gain the label requeue pending namespaces
+	ProjectRequiredLabel labels.Selector
+	// MaxProjectList caps how many projects an uncached lookup loads; a
+	// lookup that misses within the cap fails instead of reading further
+	MaxProjectList int
+	// BackfillClusterID adds the missing clusterId label to namespaces that
+	// already carry a projectId instead of resolving them again
+	BackfillClusterID bool
+	// ResolveOwnerApps assigns namespaces owned by a Rancher App to the
+	// app's project before any owner label is consulted
+	ResolveOwnerApps bool
+	// MaxClusterClients caps how many downstream cluster clients are held,
+	// evicting the least recently used ones. Unlimited when zero.
+	MaxClusterClients int
+	// AssignmentChecksum stamps a checksum of the owner, projectId and
+	// clusterId so unchanged namespaces are skipped early
+	AssignmentChecksum bool
+	// VerifyPatches reads namespaces back after patching and requeues when
+	// the assignment did not persist
+	VerifyPatches bool
+	// ProjectNameTemplate derives the project name looked up from the owner,
+	// for example "{{ .Owner }}-apps"
+	ProjectNameTemplate *template.Template
+	// EventTypes overrides the event type (Normal or Warning) emitted for
+	// an event reason, so operators can tune event noise
+	EventTypes map[string]string
+	// QuarantineThreshold is the number of consecutive failed patches after
+	// which a namespace is annotated as quarantined and no longer patched.
+	// Disabled when zero.
+	QuarantineThreshold int
+	// GlobalProjectNames assumes project names are unique across clusters,
+	// searching all clusters and assigning the single match regardless of
+	// the namespace's cluster
+	GlobalProjectNames bool
+	// Once disables the controller and background refresh; an OnceRunner
+	// reconciles every namespace a single time instead
+	Once bool
+	// OwnerLabels are the namespace label keys holding the owner, in order.
+	// Only appOwner is read when empty. OwnerLabelPolicy decides what
+	// happens when they disagree.
+	OwnerLabels      []string
+	OwnerLabelPolicy OwnerLabelPolicy
+	// DecisionTraceLimit is the number of recent decisions kept in the
+	// status ConfigMap's decision trace annotation. Disabled when zero.
+	DecisionTraceLimit int
+	// ServiceAccountAnnotation names the namespace annotation recording the
+	// creating service account, mapped to a project name through
+	// ServiceAccountProjects before any owner label is consulted
+	ServiceAccountAnnotation string
+	ServiceAccountProjects   map[string]string
+	// ClusterListRetries is how many times a failed cluster List is retried
+	// within one refresh, waiting ClusterListRetryBackoff before the first
+	// retry and doubling it after each one
+	ClusterListRetries      int
+	ClusterListRetryBackoff time.Duration
+	// AnnotateResolutionSource stamps which source produced the project match
+	AnnotateResolutionSource bool
+	// ClusterClientWorkers bounds how many cluster clients are created in
+	// parallel during a refresh. Clients are created one at a time when zero.
+	ClusterClientWorkers int
+	// ClusterRefreshRequests triggers an immediate cluster client refresh,
+	// delayed by ClusterRefreshDebounce so bursts of notifications refresh once
+	ClusterRefreshRequests <-chan struct{}
+	ClusterRefreshDebounce time.Duration
+	// RequeueIneligible retries namespaces whose project exists but is not
+	// eligible or is terminating, with the controller's per-item backoff
+	RequeueIneligible bool
+	// ProjectPool, when set, assigns every namespace to one of these project
+	// names chosen by hashing the namespace name, before any owner is consulted
+	ProjectPool []string
+	// AnnotatePending stamps the intended project on namespaces whose
+	// assignment is deferred until it is applied
+	AnnotatePending bool
+	// Clock drives the cluster refresh, grace periods, maintenance windows and
+	// the timestamps written by the reconciler. Defaults to the real clock.
+	Clock clock.WithTicker
+	// FleetOwnerLabel names the label propagated by a Rancher Fleet bundle,
+	// for example fleet.cattle.io/bundle-name, whose value is used as the
+	// owner when no owner label, annotation or ConfigMap is present
+	FleetOwnerLabel string
+	// DownstreamTokenSecret references a Secret whose DownstreamTokenKey
+	// holds the bearer token presented to Rancher's cluster proxy, for
+	// management configs that authenticate with a client certificate
+	DownstreamTokenSecret types.NamespacedName
+	DownstreamTokenKey    string
+	// OwnerMappings consults OwnerMapping resources before any other source,
+	// treating them as the authoritative owner to project mapping
+	OwnerMappings bool
+	// SplitPatches applies the project ID first and the remaining labels and
+	// annotations in a second, best-effort patch
+	SplitPatches bool
+	// CostCenterLabel names the namespace label holding a cost center, mapped
+	// to a project name by the data of CostCenterConfigMap
+	CostCenterLabel     string
+	CostCenterConfigMap types.NamespacedName
+	// UpgradingCondition is the Cluster condition type, for example Updated,
+	// whose Unknown status marks an upgrade in progress. Assignments on an
+	// upgrading cluster are deferred. Disabled when empty.
+	UpgradingCondition string
+	// NamespaceQPS and NamespaceBurst configure a token bucket per namespace.
+	// Reconciles beyond it are requeued. Disabled when NamespaceQPS is zero.
+	NamespaceQPS   float32
+	NamespaceBurst int
+	// LabelProjectName labels namespaces with the resolved project's display name
+	LabelProjectName bool
+	// AssignmentHook is called after every successful assignment. Its errors
+	// are logged unless FailOnHookError fails the reconcile.
+	AssignmentHook  AssignmentHook
+	FailOnHookError bool
+	// ManageProjectRBAC grants the owner group RBACRoleTemplate in the project
+	// of each assigned namespace through a ProjectRoleTemplateBinding, moving
+	// the binding when the namespace is reassigned. The group principal is
+	// RBACGroupPrincipalPrefix followed by the owner.
+	ManageProjectRBAC        bool
+	RBACRoleTemplate         string
+	RBACGroupPrincipalPrefix string
+	// MaxConcurrentPatches bounds the namespace patches in flight against a
+	// single cluster. Unlimited when zero.
+	MaxConcurrentPatches int
+
+	clusterClients map[string]client.Client
+	clusterMutex   sync.RWMutex
+	// refreshMutex serializes refreshes and rebuilds of the cluster clients
+	refreshMutex       sync.Mutex
 	lastClusterRefresh time.Time
+	schemeOnce         sync.Once
+	clusterScheme      *runtime.Scheme
+	clusterSchemeErr   error
+	limiterMutex       sync.Mutex
+	clusterLimiters    map[string]flowcontrol.RateLimiter
+	projects           projectCache
+	listLatency        latencyTracker
+
+	// clientUsage tracks client use for LRU eviction
+	clientUsage clientUsage
+	// namespaceStates detects recreated namespaces by UID
+	namespaceStates namespaceStates
+	// namespaceLocks serializes writes to the same namespace from reconciles
+	// and the assignment verifier
+	namespaceLocks keyedMutex
+	// namespaceRates limits how often each namespace is reconciled
+	namespaceRates namespaceRateLimits
+	// statusUpdates queues decisions for the status ConfigMap
+	statusUpdates statusUpdates
+	// patchSlots bounds concurrent patches per cluster
+	patchSlots clusterPatchSlots
+	// patchFailures counts consecutive failed patches for quarantine
+	patchFailures patchFailures
+	// namespaceRecords keeps the last decision per namespace for the debug endpoint
+	namespaceRecords namespaceRecords
+	// clusterAvailable feeds namespaces of clusters that gained a client to the controller
+	clusterAvailable chan event.GenericEvent
+	// patched remembers pre-patch resourceVersions to detect stale cache reads
+	patched patchedVersions
+	// clusterNotReadySince records when each cluster was first seen not ready
+	clusterNotReadySince map[string]time.Time
+	// upgradingClusters holds the clusters found upgrading by the last refresh
+	upgradingClusters map[string]bool
 }
 
 //+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=management.cattle.io,resources=projects,verbs=get;list;watch
 //+kubebuilder:rbac:groups=management.cattle.io,resources=clusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=management.cattle.io,resources=projectroletemplatebindings,verbs=list;create;delete
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;create;update
+//+kubebuilder:rbac:groups=project.cattle.io,resources=apps,verbs=get;list
+//+kubebuilder:rbac:groups=rancher-operator.quiknode.io,resources=ownermappings,verbs=get;list;watch
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	// Tag logs, decisions and metric exemplars of this reconcile with one correlation ID
+	correlationID := string(uuid.NewUUID())
+	logger := log.FromContext(ctx).WithValues("correlationId", correlationID)
+	ctx = log.IntoContext(ctx, logger)
+
+	// Record how this reconcile ended once it returns
+	start := r.clock().Now()
+	decision := Decision{Namespace: req.Name, Outcome: DecisionSkipped, Reason: ReasonSkipped, CorrelationID: correlationID}
+	defer func() {
+		decision.Duration = r.clock().Since(start)
+		decision.requeueAfter = result.RequeueAfter
+		r.recordDecision(ctx, decision, err)
+	}()
+
+	// Convert panics into errors so a malformed object cannot crash the worker
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			reconcilePanics.Inc()
+			err = fmt.Errorf("panic during reconcile of namespace %s: %v", req.Name, recovered)
+			logger.Error(err, "recovered from panic", "stack", string(debug.Stack()))
+			result = ctrl.Result{}
+		}
+	}()
 
 	// Determine which cluster this namespace belongs to from the request
 	// The request may contain cluster information in the namespace field or we need to detect it
 	clusterID, namespaceClient := r.getClusterClient(ctx, req)
+	decision.ClusterID = clusterID
+	decision.routedClusterID = clusterID
+	// A namespace of another cluster must never be read or patched on the
+	// management cluster; retry until the cluster has a client
 	if namespaceClient == nil {
-		logger.V(1).Info("no cluster client available, using management cluster client", "namespace", req.Name)
-		namespaceClient = r.Client
-		clusterID = "local"
+		return ctrl.Result{}, fmt.Errorf("no client available for cluster %s", clusterID)
+	}
+
+	// Defer excessive reconciles of one namespace before any work is done
+	if allowed, delay := r.namespaceRates.allow(clusterID+"/"+req.Name, r.NamespaceQPS, r.NamespaceBurst); !allowed {
+		decision.Reason = ReasonThrottled
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// Reconciles and verifier fixes of the same namespace wait for each other,
+	// so the later one sees the earlier one's patch
+	unlock := r.namespaceLocks.lock(clusterID + "/" + req.Name)
+	defer unlock()
+
+	// Only namespaces matching the allowlist are eligible for assignment
+	if r.NamespaceAllowlist != nil && !r.NamespaceAllowlist.MatchString(req.Name) {
+		decision.Reason = ReasonNotAllowed
+		return ctrl.Result{}, nil
+	}
+
+	// Slow down while the management API is answering project Lists slowly
+	if delay := r.listLatency.backoff(r.LatencyThreshold, r.clock().Now()); delay > 0 {
+		decision.Reason = ReasonThrottled
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// Avoid churn on clusters in the middle of an upgrade
+	if r.clusterUpgrading(clusterID) {
+		decision.Reason = ReasonClusterUpgrading
+		return ctrl.Result{RequeueAfter: clusterUpgradeRequeueDelay}, nil
 	}
 
 	// Fetch the Namespace instance from the appropriate cluster
@@ -74,92 +468,352 @@ func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if err := namespaceClient.Get(ctx, types.NamespacedName{Name: req.Name}, namespace); err != nil {
 		if errors.IsNotFound(err) {
 			// Namespace was deleted, nothing to do
+			r.patched.forget(clusterID, req.Name)
+			r.namespaceStates.forget(clusterID, req.Name)
+			r.patchFailures.reset(clusterID, req.Name)
+			r.namespaceRates.forget(clusterID + "/" + req.Name)
+			decision.Reason = ReasonNamespaceNotFound
 			return ctrl.Result{}, nil
 		}
-		logger.Error(err, "unable to fetch Namespace", "clusterId", clusterID)
-		return ctrl.Result{}, err
+		r.handleClusterAuthError(ctx, clusterID, err)
+		return ctrl.Result{}, clusterError(clusterID, "unable to fetch namespace "+req.Name, err)
 	}
 
-	// Check if namespace has appOwner label
-	appOwner, exists := namespace.Labels[appOwnerLabel]
-	if !exists || appOwner == "" {
-		logger.V(1).Info("namespace does not have appOwner label, skipping", "namespace", namespace.Name, "clusterId", clusterID)
+	// Name every candidate when resolution fails on an ambiguous match
+	defer func() {
+		if err != nil {
+			r.reportAmbiguity(namespace, err)
+		}
+	}()
+
+	// Reset state cached for a previous object with the same name so a
+	// recreated namespace is resolved from scratch
+	switch r.namespaceStates.observe(clusterID, namespace) {
+	case transitionRecreated:
+		logger.Info("namespace was recreated, resetting cached state", "namespace", namespace.Name, "uid", namespace.UID)
+		r.patched.forget(clusterID, namespace.Name)
+	case transitionLeftTerminating:
+		logger.Info("namespace left the Terminating phase, resetting cached state", "namespace", namespace.Name, "uid", namespace.UID)
+		r.patched.forget(clusterID, namespace.Name)
+		r.eventf(namespace, corev1.EventTypeWarning, "UnexpectedPhaseTransition",
+			"Namespace left the Terminating phase; project assignment will be re-resolved")
+	}
+
+	// Quarantined namespaces are left alone until the annotation is removed
+	if namespace.Annotations[quarantinedAnnotation] != "" {
+		decision.Reason = ReasonQuarantined
 		return ctrl.Result{}, nil
 	}
 
-	logger.Info("processing namespace with appOwner label", "namespace", namespace.Name, "appOwner", appOwner, "clusterId", clusterID)
+	// Namespaces being deleted are not assigned
+	if namespaceTerminating(namespace) {
+		decision.Reason = ReasonNamespaceTerminating
+		return ctrl.Result{}, nil
+	}
+
+	// A read at the version we last patched comes from a cache that has not
+	// caught up yet; wait for it instead of patching the same change again
+	if r.patched.stale(clusterID, namespace.Name, namespace.ResourceVersion, r.clock().Now()) {
+		decision.Reason = ReasonStaleCache
+		return ctrl.Result{RequeueAfter: staleCacheRequeueDelay}, nil
+	}
+	readVersion := namespace.ResourceVersion
+
+	// Namespaces assigned by older tooling may lack the clusterId label; fill it in without re-resolving
+	if r.BackfillClusterID && !r.PlanOnly {
+		backfilled, err := r.backfillClusterID(ctx, namespaceClient, namespace, clusterID)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if backfilled {
+			decision.ProjectID = namespace.Labels[rancherProjectIDLabel]
+			decision.Reason = ReasonClusterIDBackfilled
+			return ctrl.Result{}, nil
+		}
+	}
 
-	// Find the Rancher Project by name (case-insensitive)
-	// Projects are managed on the management cluster, so use the management client
-	project, err := r.findProjectByName(ctx, appOwner, clusterID)
+	// Run the resolution chain shared with the webhook and the debug endpoint
+	res, err := r.resolveNamespace(ctx, namespaceClient, namespace, clusterID)
+	appOwner, ref, ineligibleErr := res.Owner, res.Ref, res.Ineligible
+	decision.Owner = appOwner
 	if err != nil {
-		logger.Error(err, "unable to find project", "projectName", appOwner, "clusterId", clusterID)
 		return ctrl.Result{}, err
 	}
+	switch res.Skip {
+	case ReasonOwnerDisagreement:
+		r.eventf(namespace, corev1.EventTypeWarning, string(ReasonOwnerDisagreement), "%v", res.SkipErr)
+		decision.Reason = ReasonOwnerDisagreement
+		decision.Message = res.SkipErr.Error()
+		return ctrl.Result{}, nil
+	case ReasonNoOwnerLabel:
+		decision.Reason = ReasonNoOwnerLabel
+		return ctrl.Result{}, nil
+	case ReasonOwnerEmpty:
+		decision.Reason = ReasonOwnerEmpty
+		return ctrl.Result{}, nil
+	}
+	decision.resolvedFrom = res.From
+
+	// Projects that exist but are not eligible yet may become eligible later
+	if ref == nil && ineligibleErr != nil {
+		r.eventf(namespace, corev1.EventTypeNormal, string(ReasonProjectIneligible), "%v", ineligibleErr)
+		decision.Reason = ReasonProjectIneligible
+		decision.Message = ineligibleErr.Error()
+		return ctrl.Result{Requeue: r.RequeueIneligible}, nil
+	}
 
 	// If project doesn't exist, skip (project creation removed)
-	if project == nil {
-		logger.Info("project not found, skipping namespace assignment", "projectName", appOwner, "namespace", namespace.Name, "clusterId", clusterID)
+	if ref == nil {
+		r.eventf(namespace, corev1.EventTypeNormal, string(ReasonProjectNotFound),
+			"No project matches owner %q", appOwner)
+		decision.Reason = ReasonProjectNotFound
 		return ctrl.Result{}, nil
 	}
 
-	// Get project ID and cluster ID from the project
-	projectID := project.GetName()
-	projectClusterID := r.extractClusterID(projectID)
+	// Never assign namespaces to a project that is being deleted
+	if ref.Project != nil && projectTerminating(ref.Project) {
+		decision.Reason = ReasonProjectTerminating
+		return ctrl.Result{Requeue: r.RequeueIneligible}, nil
+	}
+
+	// Refuse protected projects such as System unless the namespace opts in
+	if r.isProtectedProject(ref, appOwner) && !strings.EqualFold(namespace.Annotations[allowProtectedProjectAnnotation], "true") {
+		r.eventf(namespace, corev1.EventTypeWarning, "ProtectedProject",
+			"Project %s is protected; set %s=true to allow assignment", ref.ProjectID, allowProtectedProjectAnnotation)
+		decision.Reason = ReasonProtectedProject
+		return ctrl.Result{}, nil
+	}
+
+	projectID := ref.ProjectID
+	projectClusterID := ref.ClusterID
 
 	// Use the project's cluster ID if available, otherwise use the detected cluster ID
 	if projectClusterID == "" {
 		projectClusterID = clusterID
 	}
 
+	// Write the canonical ID of the management cluster instead of the "local" alias
+	if projectClusterID == "local" && r.LocalClusterID != "" {
+		projectClusterID = r.LocalClusterID
+	}
+
 	if projectID == "" {
-		logger.Info("project ID is empty, skipping", "projectName", appOwner, "clusterId", clusterID)
+		decision.Reason = ReasonProjectIDEmpty
 		return ctrl.Result{}, nil
 	}
 
-	// Check if namespace is already correctly assigned to this project
-	if existingProjectID, hasProject := namespace.Labels[rancherProjectIDLabel]; hasProject && existingProjectID == projectID {
-		// Also check if cluster ID matches (if present)
-		if existingClusterID, hasClusterID := namespace.Labels[rancherClusterIDLabel]; hasClusterID {
-			if existingClusterID == projectClusterID {
-				logger.V(1).Info("namespace already correctly assigned to project", "namespace", namespace.Name, "projectId", projectID, "clusterId", projectClusterID)
-				return ctrl.Result{}, nil
-			}
-		} else if projectClusterID == clusterID {
-			// If no cluster ID label but the project cluster matches detected cluster, consider it correct
-			logger.V(1).Info("namespace already correctly assigned to project", "namespace", namespace.Name, "projectId", projectID, "clusterId", projectClusterID)
+	decision.ProjectID = projectID
+	decision.ClusterID = projectClusterID
+
+	// Unchanged assignment inputs need no further processing
+	if r.checksumMatches(namespace, appOwner, projectID, projectClusterID) {
+		decision.Reason = ReasonAlreadyAssigned
+		return ctrl.Result{}, nil
+	}
+
+	// Check if namespace is already correctly assigned to this project. Optional
+	// metadata may still be missing, so the shortcut only applies when none is written.
+	if !r.writesOptionalMetadata() && r.alreadyAssigned(namespace, projectID, projectClusterID, clusterID) {
+		decision.Reason = ReasonAlreadyAssigned
+		return ctrl.Result{}, nil
+	}
+
+	// Carry annotations such as the assignment history forward from a predecessor namespace
+	if err := r.carryForwardAnnotations(ctx, namespaceClient, namespace); err != nil {
+		return ctrl.Result{}, clusterError(clusterID, "unable to copy annotations from predecessor namespace", err)
+	}
+
+	// Apply the conflict policy when the namespace is already assigned elsewhere
+	if existingProjectID := namespace.Labels[rancherProjectIDLabel]; existingProjectID != "" && existingProjectID != projectID {
+		switch r.ConflictPolicy {
+		case ConflictPolicyKeepExisting:
+			decision.Reason = ReasonConflict
+			decision.Message = "namespace is assigned to " + existingProjectID
+			return ctrl.Result{}, nil
+		case ConflictPolicyEventAndSkip:
+			r.eventf(namespace, corev1.EventTypeWarning, "ProjectConflict",
+				"appOwner %q resolves to project %s but namespace is assigned to %s", appOwner, projectID, existingProjectID)
+			decision.Reason = ReasonConflict
+			decision.Message = "namespace is assigned to " + existingProjectID
 			return ctrl.Result{}, nil
 		}
 	}
 
 	// Update namespace with project labels and annotations using the appropriate cluster client
-	if err := r.updateNamespaceWithProject(ctx, namespaceClient, namespace, projectID, projectClusterID); err != nil {
-		logger.Error(err, "unable to update namespace with project assignment", "namespace", namespace.Name, "clusterId", clusterID)
+	updated, err := r.updateNamespaceWithProject(ctx, namespaceClient, namespace, appOwner, ref, projectClusterID)
+	if err != nil {
+		if isPlanned(err) {
+			decision.Reason = ReasonPlanned
+			return ctrl.Result{}, nil
+		}
+		if wait, deferred := windowDeferral(err); deferred {
+			r.markPending(ctx, namespaceClient, namespace, projectID)
+			decision.Reason = ReasonDeferred
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		if isNotPersisted(err) {
+			r.recordPatchFailure(ctx, namespaceClient, namespace, clusterID)
+			r.markPending(ctx, namespaceClient, namespace, projectID)
+			decision.Reason = ReasonNotPersisted
+			decision.Message = err.Error()
+			return ctrl.Result{RequeueAfter: notPersistedRequeueDelay}, nil
+		}
+		if isUnpatchable(err) {
+			// Retrying cannot succeed until the namespace or its admission policy changes
+			r.eventf(namespace, corev1.EventTypeWarning, "AssignmentRejected",
+				"Dry-run patch assigning project %s was rejected: %v", projectID, err)
+			decision.Reason = ReasonUnpatchable
+			decision.Message = err.Error()
+			return ctrl.Result{}, nil
+		}
+		r.handleClusterAuthError(ctx, clusterID, err)
+		r.recordPatchFailure(ctx, namespaceClient, namespace, clusterID)
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("successfully assigned namespace to project", "namespace", namespace.Name, "projectId", projectID, "clusterId", projectClusterID)
+	if !updated {
+		decision.Reason = ReasonAlreadyAssigned
+		return ctrl.Result{}, nil
+	}
+
+	r.patched.record(clusterID, namespace.Name, readVersion, r.clock().Now())
+	r.patchFailures.reset(clusterID, namespace.Name)
+	decision.Outcome = DecisionAssigned
+	decision.Reason = ReasonAssigned
+
+	// Move the owner group's binding along with the namespace
+	if r.ManageProjectRBAC {
+		if err := r.syncProjectRoleBinding(ctx, namespace, appOwner, ref, clusterID, projectClusterID); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Let integrations react to the new assignment
+	if err := r.runAssignmentHook(ctx, namespace, ref, projectClusterID); err != nil {
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
+// namespaceOwner returns the owner of a namespace, consulting the appOwner
+// label first and then each configured fallback source in order
+func (r *NamespaceReconciler) namespaceOwner(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, clusterID string) (string, ResolutionSource, error) {
+	// Check if namespace has appOwner label, or one of the configured owner labels
+	owner, err := r.labelOwner(namespace)
+	if err != nil || owner != "" {
+		return owner, SourceLabel, err
+	}
+
+	// Fall back to the configured owner annotation
+	owner, err = r.annotationOwner(namespace)
+	if err != nil || owner != "" {
+		return owner, SourceAnnotation, err
+	}
+
+	// Fall back to the owner ConfigMap inside the namespace
+	owner, err = r.configMapOwner(ctx, namespaceClient, namespace)
+	if err != nil {
+		return "", "", clusterError(clusterID, "unable to read owner ConfigMap", err)
+	}
+	if owner != "" {
+		return owner, SourceConfigMap, nil
+	}
+
+	// Fall back to the label propagated from the Fleet bundle
+	if r.FleetOwnerLabel != "" {
+		if owner := namespace.Labels[r.FleetOwnerLabel]; owner != "" {
+			return owner, SourceFleet, nil
+		}
+	}
+
+	// Fall back to the display name Rancher stores on the namespace
+	if r.UseDisplayNameOwner {
+		if owner := namespace.Annotations[rancherDisplayNameAnnotation]; owner != "" {
+			return owner, SourceDisplayName, nil
+		}
+	}
+
+	// Subnamespaces may inherit the owner from their HNC parent
+	if r.InheritParentOwner {
+		owner, err = r.parentOwner(ctx, namespaceClient, namespace)
+		if err != nil {
+			return "", "", clusterError(clusterID, "unable to read parent namespace owner", err)
+		}
+		if owner != "" {
+			return owner, SourceParent, nil
+		}
+	}
+	return "", "", nil
+}
+
+// viaCluster returns the cluster pinned by the namespace's via-cluster
+// annotation, read from the management cluster cache
+func (r *NamespaceReconciler) viaCluster(ctx context.Context, req ctrl.Request) string {
+	if req.Name == "" {
+		return ""
+	}
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: req.Name}, namespace); err != nil {
+		return ""
+	}
+	return namespace.Annotations[viaClusterAnnotation]
+}
+
 // getClusterClient determines which cluster client to use based on the request
-// Returns the cluster ID and the appropriate client
+// Returns the cluster ID and the appropriate client. The client is nil when
+// the namespace is routed to a cluster that has no client.
 // For now, we primarily watch the management cluster. Downstream cluster access
 // will be handled through Rancher's cluster proxy when needed.
 func (r *NamespaceReconciler) getClusterClient(ctx context.Context, req ctrl.Request) (string, client.Client) {
+	viaCluster := r.viaCluster(ctx, req)
+
 	r.clusterMutex.RLock()
 	defer r.clusterMutex.RUnlock()
 
+	// The via-cluster annotation overrides every other routing rule
+	if viaCluster != "" {
+		if viaCluster == "local" {
+			return "local", r.Client
+		}
+		clusterClient, ok := r.clusterClients[viaCluster]
+		if ok {
+			r.clientUsage.touch(viaCluster, r.clock().Now())
+		}
+		return viaCluster, clusterClient
+	}
+
+	// In single-cluster mode every namespace is routed to the pinned cluster
+	if r.SingleCluster != "" && r.SingleCluster != "local" {
+		r.clientUsage.touch(r.SingleCluster, r.clock().Now())
+		return r.SingleCluster, r.clusterClients[r.SingleCluster]
+	}
+
 	// For now, we're watching the management cluster directly
 	// In the future, we can enhance this to detect which cluster the namespace belongs to
 	// by checking namespace labels or using Rancher's cluster mapping
 	return "local", r.Client
 }
 
+// clusterRefresher runs refreshClusterClients under the manager. It runs on
+// every replica, not just the leader, since the debug endpoint also reads
+// namespaces through the cluster clients.
+type clusterRefresher struct {
+	reconciler *NamespaceReconciler
+}
+
+func (c clusterRefresher) Start(ctx context.Context) error {
+	c.reconciler.refreshClusterClients(ctx)
+	return nil
+}
+
+func (c clusterRefresher) NeedLeaderElection() bool {
+	return false
+}
+
 // refreshClusterClients periodically refreshes the list of downstream clusters and creates clients
 func (r *NamespaceReconciler) refreshClusterClients(ctx context.Context) {
-	ticker := time.NewTicker(clusterRefreshInterval)
-	defer ticker.Stop()
+	ticker := r.clock().NewTicker(clusterRefreshInterval)
+	defer func() { ticker.Stop() }()
 
 	// Initial refresh
 	r.doRefreshClusterClients(ctx)
@@ -168,100 +822,222 @@ func (r *NamespaceReconciler) refreshClusterClients(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-ticker.C():
+			r.doRefreshClusterClients(ctx)
+		case <-r.ClusterRefreshRequests:
+			// Coalesce notifications arriving within the debounce period
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.clock().After(r.ClusterRefreshDebounce):
+			}
+			select {
+			case <-r.ClusterRefreshRequests:
+			default:
+			}
+			log.FromContext(ctx).Info("cluster refresh requested")
 			r.doRefreshClusterClients(ctx)
+
+			// Restart the interval from the requested refresh
+			ticker.Stop()
+			ticker = r.clock().NewTicker(clusterRefreshInterval)
 		}
 	}
 }
 
 func (r *NamespaceReconciler) doRefreshClusterClients(ctx context.Context) {
+	r.refreshMutex.Lock()
+	defer r.refreshMutex.Unlock()
+
 	logger := log.FromContext(ctx)
 	logger.Info("refreshing cluster clients")
 
-	// List all clusters from Rancher
+	// List all clusters from Rancher, or from Cluster API
 	clusterList := &unstructured.UnstructuredList{}
-	clusterList.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "management.cattle.io",
-		Version: "v3",
-		Kind:    "ClusterList",
-	})
+	clusterList.SetGroupVersionKind(r.clusterListGVK())
 
-	if err := r.List(ctx, clusterList); err != nil {
+	if err := r.listClusters(ctx, clusterList); err != nil {
 		logger.Error(err, "unable to list clusters")
 		return
 	}
 
 	newClusterClients := make(map[string]client.Client)
+	var readyClusterIDs []string
+	upgrading := make(map[string]bool)
 
-	// Create clients for each cluster
+	// Select the clusters that need a client
 	for i := range clusterList.Items {
 		cluster := &clusterList.Items[i]
-		clusterID := cluster.GetName()
+		clusterID := r.discoveredClusterID(cluster)
 
 		// Skip the local cluster (management cluster) - we already have a client for it
 		if clusterID == "local" {
 			continue
 		}
 
-		// Get cluster status to check if it's ready
-		status, found, err := unstructured.NestedMap(cluster.Object, "status")
-		if err != nil || !found {
-			logger.V(1).Info("cluster status not found, skipping", "clusterId", clusterID)
+		// The management cluster may also be registered under another ID, reuse the direct client for it
+		if r.isManagementCluster(cluster) {
+			logger.V(1).Info("cluster is the management cluster, reusing direct client", "clusterId", clusterID)
+			newClusterClients[clusterID] = r.Client
 			continue
 		}
 
-		// Check if cluster is ready
-		conditions, found, _ := unstructured.NestedSlice(status, "conditions")
-		if !found {
-			logger.V(1).Info("cluster conditions not found, skipping", "clusterId", clusterID)
+		if !r.clusterReady(cluster) {
+			// Keep the existing client while the cluster is within its not-ready grace period
+			if existing := r.clientWithinGracePeriod(clusterID); existing != nil {
+				logger.V(1).Info("cluster not ready, keeping client during grace period", "clusterId", clusterID)
+				newClusterClients[clusterID] = existing
+				continue
+			}
+			logger.V(1).Info("cluster not ready, skipping", "clusterId", clusterID)
 			continue
 		}
+		r.markClusterReady(clusterID)
+		readyClusterIDs = append(readyClusterIDs, clusterID)
 
-		ready := false
-		for _, cond := range conditions {
-			if condMap, ok := cond.(map[string]interface{}); ok {
-				if condType, ok := condMap["type"].(string); ok && condType == "Ready" {
-					if condStatus, ok := condMap["status"].(string); ok && condStatus == "True" {
-						ready = true
-						break
-					}
-				}
-			}
+		if r.clusterIsUpgrading(cluster) {
+			logger.Info("cluster is upgrading, deferring assignments", "clusterId", clusterID)
+			upgrading[clusterID] = true
 		}
+	}
 
-		if !ready {
-			logger.V(1).Info("cluster not ready, skipping", "clusterId", clusterID)
-			continue
+	// Only create as many clients as can be held, keeping the recently used ones
+	if r.MaxClusterClients > 0 {
+		admitted := r.clientUsage.admit(readyClusterIDs, r.MaxClusterClients-len(newClusterClients))
+		if skipped := len(readyClusterIDs) - len(admitted); skipped > 0 {
+			logger.Info("cluster client limit reached, skipping least recently used clusters", "skipped", skipped, "max", r.MaxClusterClients)
 		}
+		readyClusterIDs = admitted
+	}
+	r.createClusterClients(ctx, readyClusterIDs, newClusterClients)
+	r.setUpgradingClusters(upgrading)
+	r.setClusterClients(ctx, newClusterClients)
+}
 
-		// Create a client for this cluster using Rancher's cluster proxy
-		clusterClient, err := r.createClusterClient(ctx, clusterID)
-		if err != nil {
-			logger.Error(err, "unable to create client for cluster", "clusterId", clusterID)
-			continue
-		}
+// clusterListOptions narrows cluster discovery to clusters matching the configured selector
+func (r *NamespaceReconciler) clusterListOptions() []client.ListOption {
+	if r.ClusterSelector == nil || r.ClusterSelector.Empty() {
+		return nil
+	}
+	return []client.ListOption{client.MatchingLabelsSelector{Selector: r.ClusterSelector}}
+}
+
+// clientWithinGracePeriod records that the cluster is not ready and returns
+// its current client if it has been not ready for less than the grace period
+func (r *NamespaceReconciler) clientWithinGracePeriod(clusterID string) client.Client {
+	if r.NotReadyGracePeriod <= 0 {
+		return nil
+	}
+
+	r.clusterMutex.Lock()
+	defer r.clusterMutex.Unlock()
 
-		newClusterClients[clusterID] = clusterClient
-		logger.Info("created client for cluster", "clusterId", clusterID)
+	if r.clusterNotReadySince == nil {
+		r.clusterNotReadySince = make(map[string]time.Time)
+	}
+	since, ok := r.clusterNotReadySince[clusterID]
+	if !ok {
+		since = r.clock().Now()
+		r.clusterNotReadySince[clusterID] = since
+	}
+
+	if r.clock().Since(since) >= r.NotReadyGracePeriod {
+		return nil
+	}
+	return r.clusterClients[clusterID]
+}
+
+// markClusterReady clears the not-ready timestamp of the cluster
+func (r *NamespaceReconciler) markClusterReady(clusterID string) {
+	r.clusterMutex.Lock()
+	defer r.clusterMutex.Unlock()
+
+	delete(r.clusterNotReadySince, clusterID)
+}
+
+// setClusterClients replaces the cluster clients map with the refreshed clients
+func (r *NamespaceReconciler) setClusterClients(ctx context.Context, newClusterClients map[string]client.Client) {
+	logger := log.FromContext(ctx)
+
+	// Bound the number of clients held, dropping the least recently used
+	if evicted := r.clientUsage.evict(newClusterClients, r.MaxClusterClients); len(evicted) > 0 {
+		logger.Info("evicted least recently used cluster clients", "evicted", evicted, "max", r.MaxClusterClients)
 	}
 
 	// Update cluster clients map
 	r.clusterMutex.Lock()
+	available := newlyAvailableClusters(r.clusterClients, newClusterClients)
 	r.clusterClients = newClusterClients
-	r.lastClusterRefresh = time.Now()
+	r.lastClusterRefresh = r.clock().Now()
 	r.clusterMutex.Unlock()
 
+	healthyClusterClients.Set(float64(len(newClusterClients)))
+
 	logger.Info("cluster clients refreshed", "clusterCount", len(newClusterClients))
+
+	// Namespaces skipped while their cluster had no client are retried now
+	r.enqueueClusterNamespaces(ctx, available)
 }
 
-// createClusterClient creates a Kubernetes client for a downstream cluster using Rancher's cluster proxy
+// handleClusterAuthError rebuilds the client for a downstream cluster when a
+// request through the cluster proxy was rejected as unauthorized, so an expired
+// token is not reused until the next periodic refresh. The rebuild goes
+// through the same pool and swap as a refresh; a refresh already in progress
+// rebuilds every client, so the rebuild is skipped then.
+func (r *NamespaceReconciler) handleClusterAuthError(ctx context.Context, clusterID string, err error) {
+	if clusterID == "" || clusterID == "local" || !errors.IsUnauthorized(err) {
+		return
+	}
+	if !r.refreshMutex.TryLock() {
+		return
+	}
+	defer r.refreshMutex.Unlock()
+
+	logger := log.FromContext(ctx)
+
+	// Only held clients are rebuilt; evicted clusters wait for a refresh to be admitted
+	r.clusterMutex.RLock()
+	_, held := r.clusterClients[clusterID]
+	newClusterClients := make(map[string]client.Client, len(r.clusterClients))
+	for id, clusterClient := range r.clusterClients {
+		if id != clusterID {
+			newClusterClients[id] = clusterClient
+		}
+	}
+	r.clusterMutex.RUnlock()
+	if !held {
+		return
+	}
+
+	logger.Info("cluster proxy rejected credentials, rebuilding client", "clusterId", clusterID)
+	r.createClusterClients(ctx, []string{clusterID}, newClusterClients)
+	r.setClusterClients(ctx, newClusterClients)
+}
+
+// createClusterClient creates a Kubernetes client for a downstream cluster
+// using Rancher's cluster proxy, or the kubeconfig of a Cluster API cluster
 func (r *NamespaceReconciler) createClusterClient(ctx context.Context, clusterID string) (client.Client, error) {
+	// Cluster API clusters are reached with their own kubeconfig instead
+	if r.ClusterSource == ClusterSourceCAPI {
+		return r.createCAPIClusterClient(ctx, clusterID)
+	}
+
 	// Get the base REST config from the manager
 	config := r.Manager.GetConfig()
 
 	// Create a new config for the cluster proxy
 	clusterConfig := rest.CopyConfig(config)
 
+	// The cluster proxy authenticates requests by bearer token
+	if err := r.applyDownstreamCredentials(ctx, clusterConfig, clusterID); err != nil {
+		return nil, err
+	}
+
+	// Throttle requests to this cluster with a limiter that survives client rebuilds
+	if limiter := r.clusterRateLimiter(clusterID); limiter != nil {
+		clusterConfig.RateLimiter = limiter
+	}
+
 	// Rancher's cluster proxy URL format: /k8s/clusters/<cluster-id>
 	// We need to modify the API path to include the cluster ID
 	// The cluster proxy is accessed through the management cluster's API server
@@ -273,8 +1049,13 @@ func (r *NamespaceReconciler) createClusterClient(ctx context.Context, clusterID
 		}
 	}
 
+	scheme, err := r.downstreamScheme()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a new client for this cluster
-	clusterClient, err := client.New(clusterConfig, client.Options{Scheme: r.Scheme})
+	clusterClient, err := client.New(clusterConfig, client.Options{Scheme: scheme})
 	if err != nil {
 		return nil, fmt.Errorf("unable to create client for cluster %s: %w", clusterID, err)
 	}
@@ -282,53 +1063,208 @@ func (r *NamespaceReconciler) createClusterClient(ctx context.Context, clusterID
 	return clusterClient, nil
 }
 
-// findProjectByName searches for a Rancher Project by its display name
-func (r *NamespaceReconciler) findProjectByName(ctx context.Context, projectName string, clusterID string) (client.Object, error) {
-	logger := log.FromContext(ctx)
+// clusterRateLimiter returns the token bucket for the cluster, creating it on
+// first use. It returns nil when per-cluster limiting is disabled.
+func (r *NamespaceReconciler) clusterRateLimiter(clusterID string) flowcontrol.RateLimiter {
+	if r.DownstreamQPS <= 0 {
+		return nil
+	}
 
-	logger.V(1).Info("searching for project", "projectName", projectName, "clusterId", clusterID)
+	r.limiterMutex.Lock()
+	defer r.limiterMutex.Unlock()
 
-	// List all projects, optionally filtered by cluster
-	projectList := &unstructured.UnstructuredList{}
-	projectList.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "management.cattle.io",
-		Version: "v3",
-		Kind:    "ProjectList",
-	})
+	if r.clusterLimiters == nil {
+		r.clusterLimiters = make(map[string]flowcontrol.RateLimiter)
+	}
+	limiter, ok := r.clusterLimiters[clusterID]
+	if !ok {
+		burst := r.DownstreamBurst
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = flowcontrol.NewTokenBucketRateLimiter(r.DownstreamQPS, burst)
+		r.clusterLimiters[clusterID] = limiter
+	}
+	return limiter
+}
 
-	var listOptions []client.ListOption
-	if clusterID != "" && clusterID != "local" {
-		// Filter by cluster namespace if specified
-		listOptions = append(listOptions, client.InNamespace(clusterID))
+// downstreamScheme returns the scheme for downstream cluster clients. Without
+// extra builders the manager scheme is reused.
+func (r *NamespaceReconciler) downstreamScheme() (*runtime.Scheme, error) {
+	if len(r.DownstreamSchemeBuilders) == 0 {
+		return r.Scheme, nil
 	}
 
-	if err := r.List(ctx, projectList, listOptions...); err != nil {
-		logger.V(1).Info("unable to list projects", "error", err)
-		return nil, fmt.Errorf("unable to list projects: %w", err)
+	r.schemeOnce.Do(func() {
+		scheme := runtime.NewScheme()
+		if err := clientgoscheme.AddToScheme(scheme); err != nil {
+			r.clusterSchemeErr = fmt.Errorf("unable to register core types on downstream scheme: %w", err)
+			return
+		}
+		if err := r.DownstreamSchemeBuilders.AddToScheme(scheme); err != nil {
+			r.clusterSchemeErr = fmt.Errorf("unable to register extra types on downstream scheme: %w", err)
+			return
+		}
+		r.clusterScheme = scheme
+	})
+
+	return r.clusterScheme, r.clusterSchemeErr
+}
+
+// findProjectByName searches for a Rancher Project by its display name
+func (r *NamespaceReconciler) findProjectByName(ctx context.Context, projectName string, clusterID string) (*unstructured.Unstructured, error) {
+	return r.findProject(ctx, projectName, clusterID, func(project *unstructured.Unstructured) bool {
+		return r.projectMatches(project, projectName)
+	})
+}
+
+// findProject searches the namespace's cluster and the fallback clusters for
+// projects accepted by match and selects one of the candidates
+func (r *NamespaceReconciler) findProject(ctx context.Context, projectName string, clusterID string, match func(*unstructured.Unstructured) bool) (*unstructured.Unstructured, error) {
+	logger := log.FromContext(ctx)
+
+	// Search the namespace's cluster first, then the fallback clusters in order
+	searchClusterIDs := append([]string{clusterID}, r.FallbackClusters...)
+	if r.GlobalProjectNames {
+		// Names are unique across clusters, so a single unfiltered search covers them all
+		searchClusterIDs = []string{""}
 	}
 
-	// Search through projects for a match by displayName or labels/annotations
-	for i := range projectList.Items {
-		project := &projectList.Items[i]
-		if r.projectMatches(project, projectName) {
-			logger.Info("found project by name match", "projectName", projectName, "projectId", project.GetName(), "clusterId", clusterID)
-			return project, nil
+	searched := make(map[string]bool)
+	ineligible := 0
+	for _, searchClusterID := range searchClusterIDs {
+		if searched[searchClusterID] {
+			continue
+		}
+		searched[searchClusterID] = true
+
+		logger.V(1).Info("searching for project", "projectName", projectName, "clusterId", searchClusterID)
+
+		// List all projects, optionally filtered by cluster
+		projects, truncated, err := r.listProjects(ctx, searchClusterID)
+		if err != nil {
+			logger.V(1).Info("unable to list projects", "error", err)
+			return nil, clusterError(searchClusterID, "unable to find project "+projectName, err)
+		}
+
+		// Search through projects for a match by displayName or labels/annotations
+		var candidates []*unstructured.Unstructured
+		for i := range projects {
+			project := &projects[i]
+			if !match(project) {
+				continue
+			}
+			if !r.projectEligible(project) {
+				ineligible++
+				continue
+			}
+			candidates = append(candidates, project)
+		}
+
+		if len(candidates) == 0 {
+			if truncated {
+				return nil, clusterError(searchClusterID, "unable to find project "+projectName,
+					fmt.Errorf("project not found within the first %d projects; raise --max-project-list or enable --project-cache-ttl", r.MaxProjectList))
+			}
+			continue
+		}
+
+		// Several matches contradict the uniqueness assumption, whatever the ambiguity policy
+		if r.GlobalProjectNames && len(candidates) > 1 {
+			return nil, clusterError(clusterID, "unable to select project",
+				newAmbiguousProjectError(projectName, candidates, "across clusters"))
+		}
+
+		project, err := r.selectProject(ctx, candidates, projectName)
+		if err != nil {
+			return nil, clusterError(searchClusterID, "unable to select project", err)
 		}
+
+		logger.V(1).Info("found project by name match", "projectName", projectName, "projectId", project.GetName(), "clusterId", searchClusterID, "candidates", len(candidates))
+		return project, nil
 	}
 
+	if ineligible > 0 {
+		return nil, &projectIneligibleError{projectName: projectName, matches: ineligible}
+	}
 	return nil, nil
 }
 
-// projectMatches checks if a project matches the given name (case-insensitive)
+// selectProject applies the configured ambiguity policy to the matching projects
+func (r *NamespaceReconciler) selectProject(ctx context.Context, candidates []*unstructured.Unstructured, projectName string) (*unstructured.Unstructured, error) {
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	// Sort by namespace and name so the choice is deterministic across list calls
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].GetNamespace() != candidates[j].GetNamespace() {
+			return candidates[i].GetNamespace() < candidates[j].GetNamespace()
+		}
+		return candidates[i].GetName() < candidates[j].GetName()
+	})
+
+	switch r.AmbiguityPolicy {
+	case AmbiguityPolicyFail:
+		return nil, newAmbiguousProjectError(projectName, candidates, "")
+	case AmbiguityPolicyAnnotation:
+		var preferred []*unstructured.Unstructured
+		for _, candidate := range candidates {
+			if strings.EqualFold(candidate.GetAnnotations()[preferredProjectAnnotation], "true") {
+				preferred = append(preferred, candidate)
+			}
+		}
+		if len(preferred) != 1 {
+			return nil, newAmbiguousProjectError(projectName, candidates,
+				fmt.Sprintf("and %d are marked with %s", len(preferred), preferredProjectAnnotation))
+		}
+		return preferred[0], nil
+	case AmbiguityPolicyWeighted:
+		namespace, ok := NamespaceFromContext(ctx)
+		if !ok {
+			return candidates[0], nil
+		}
+		project := selectWeighted(candidates, namespace.Name)
+		if project == nil {
+			return nil, fmt.Errorf("%d projects match name %q and all have weight 0", len(candidates), projectName)
+		}
+		return project, nil
+	case AmbiguityPolicyRecency:
+		// Candidates are sorted, so projects created in the same second keep the lowest ID
+		newest := candidates[0]
+		for _, candidate := range candidates[1:] {
+			if candidate.GetCreationTimestamp().Time.After(newest.GetCreationTimestamp().Time) {
+				newest = candidate
+			}
+		}
+		return newest, nil
+	default:
+		return candidates[0], nil
+	}
+}
+
+// projectMatches checks if a project matches the given name. The display name
+// and aliases are compared case-insensitively, then through the configured
+// name normalizer; labels and annotations only case-insensitively.
 func (r *NamespaceReconciler) projectMatches(project *unstructured.Unstructured, projectName string) bool {
 	// First, check spec.displayName (most common location for project display name)
 	if displayName, found, err := unstructured.NestedString(project.Object, "spec", "displayName"); err == nil && found {
-		if strings.EqualFold(displayName, projectName) {
+		if r.namesMatch(displayName, projectName) {
 			return true
 		}
 	}
 
-	// Check labels
+	// Check the aliases annotation
+	if r.AliasesAnnotation != "" {
+		for _, alias := range parseAliases(project.GetAnnotations()[r.AliasesAnnotation]) {
+			if r.namesMatch(alias, projectName) {
+				return true
+			}
+		}
+	}
+
+	// Labels and annotations only match exactly, ignoring case. The normalizer
+	// is reserved for names, so it cannot make an unrelated value match.
 	labels := project.GetLabels()
 	for key, value := range labels {
 		if strings.EqualFold(value, projectName) {
@@ -359,6 +1295,29 @@ func (r *NamespaceReconciler) projectMatches(project *unstructured.Unstructured,
 	return false
 }
 
+// parseAliases reads an aliases annotation value given either as a JSON list
+// or as a comma separated string
+func parseAliases(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	var aliases []string
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &aliases); err == nil {
+			return aliases
+		}
+	}
+
+	for _, alias := range strings.Split(value, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
 // extractClusterID extracts cluster ID from project ID
 // Rancher project IDs are typically in format: c-xxxxx:p-xxxxx
 func (r *NamespaceReconciler) extractClusterID(projectID string) string {
@@ -369,62 +1328,250 @@ func (r *NamespaceReconciler) extractClusterID(projectID string) string {
 	return ""
 }
 
+// alreadyAssigned reports whether the namespace labels already point at the project
+func (r *NamespaceReconciler) alreadyAssigned(namespace *corev1.Namespace, projectID, projectClusterID, clusterID string) bool {
+	if existingProjectID, hasProject := namespace.Labels[rancherProjectIDLabel]; !hasProject || existingProjectID != projectID {
+		return false
+	}
+
+	// Also check if cluster ID matches (if present)
+	if existingClusterID, hasClusterID := namespace.Labels[rancherClusterIDLabel]; hasClusterID {
+		return existingClusterID == projectClusterID
+	}
+
+	// If no cluster ID label but the project cluster matches detected cluster, consider it correct
+	return projectClusterID == clusterID
+}
+
+// writesOptionalMetadata reports whether assignments write metadata beyond the
+// Rancher project labels, which existing assignments may still lack
+func (r *NamespaceReconciler) writesOptionalMetadata() bool {
+	return r.ResolutionAnnotation != "" || r.AnnotateConfidence || r.AnnotateResolutionSource || r.PriorityClassLabel != "" ||
+		r.LabelProjectName || r.AssignmentChecksum || r.WriteTargets == WriteAnnotationsOnly
+}
+
 // updateNamespaceWithProject updates the namespace with project assignment labels and annotations
-// Only updates if the values are different to avoid unnecessary patches
-func (r *NamespaceReconciler) updateNamespaceWithProject(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, projectID, clusterID string) error {
+// Only updates if the values are different to avoid unnecessary patches, and
+// reports whether the namespace was patched
+func (r *NamespaceReconciler) updateNamespaceWithProject(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, appOwner string, ref *ProjectRef, clusterID string) (bool, error) {
 	logger := log.FromContext(ctx)
+	projectID := ref.ProjectID
+	confidence := ref.Confidence
+	source := ""
+	if r.AnnotateResolutionSource {
+		source = string(ref.Source)
+	}
+
+	// Look up the default PriorityClass advertised by the project, if enabled
+	priorityClass := r.projectPriorityClass(ref)
+
+	// Human readable project name label, if enabled
+	projectName := r.projectNameLabelValue(ref)
+
+	// Build the resolution annotation value if enabled
+	resolution := ""
+	if r.ResolutionAnnotation != "" {
+		data, err := json.Marshal(projectResolution{Owner: appOwner, ProjectID: projectID, ClusterID: clusterID})
+		if err != nil {
+			return false, fmt.Errorf("unable to encode resolution annotation: %w", err)
+		}
+		resolution = string(data)
+	}
+
+	// Checksum of the assignment inputs, compared early in Reconcile
+	checksum := ""
+	if r.AssignmentChecksum {
+		checksum = assignmentChecksum(appOwner, projectID, clusterID)
+	}
 
 	// Check if update is needed
 	needsUpdate := false
+	writeLabels := r.WriteTargets != WriteAnnotationsOnly
+	writeAnnotations := r.WriteTargets != WriteLabelsOnly
 
-	// Check if project ID label needs updating
-	if existingProjectID, exists := namespace.Labels[rancherProjectIDLabel]; !exists || existingProjectID != projectID {
-		needsUpdate = true
+	if writeLabels {
+		// Check if project ID label needs updating
+		if existingProjectID, exists := namespace.Labels[rancherProjectIDLabel]; !exists || existingProjectID != projectID {
+			needsUpdate = true
+		}
+
+		// Check if cluster ID label needs updating
+		if clusterID != "" {
+			if existingClusterID, exists := namespace.Labels[rancherClusterIDLabel]; !exists || existingClusterID != clusterID {
+				needsUpdate = true
+			}
+		}
+
+		// Check if PriorityClass label needs updating
+		if priorityClass != "" && namespace.Labels[r.PriorityClassLabel] != priorityClass {
+			needsUpdate = true
+		}
+
+		// Check if project name label needs updating
+		if projectName != "" && namespace.Labels[projectNameLabel] != projectName {
+			needsUpdate = true
+		}
 	}
 
-	// Check if cluster ID label needs updating
-	if clusterID != "" {
-		if existingClusterID, exists := namespace.Labels[rancherClusterIDLabel]; !exists || existingClusterID != clusterID {
+	if writeAnnotations {
+		// Check if annotation needs updating
+		if existingProjectAnnotation, exists := namespace.Annotations[rancherProjectIDAnnotation]; !exists || existingProjectAnnotation != projectID {
+			needsUpdate = true
+		}
+
+		// Check if resolution annotation needs updating
+		if resolution != "" && namespace.Annotations[r.ResolutionAnnotation] != resolution {
+			needsUpdate = true
+		}
+
+		// Check if confidence annotation needs updating
+		if r.AnnotateConfidence && confidence != "" && namespace.Annotations[confidenceAnnotation] != string(confidence) {
+			needsUpdate = true
+		}
+
+		// Check if resolution source annotation needs updating
+		if source != "" && namespace.Annotations[resolutionSourceAnnotation] != source {
+			needsUpdate = true
+		}
+
+		// Check if checksum annotation needs updating
+		if checksum != "" && namespace.Annotations[assignmentChecksumAnnotation] != checksum {
 			needsUpdate = true
 		}
 	}
 
-	// Check if annotation needs updating
-	if existingProjectAnnotation, exists := namespace.Annotations[rancherProjectIDAnnotation]; !exists || existingProjectAnnotation != projectID {
+	// A pending project annotation left by a deferred assignment is cleared
+	if _, pending := namespace.Annotations[pendingProjectAnnotation]; pending {
 		needsUpdate = true
 	}
 
 	// If no update needed, skip
 	if !needsUpdate {
 		logger.V(1).Info("namespace already has correct project assignment, skipping update", "namespace", namespace.Name, "projectId", projectID, "clusterId", clusterID)
-		return nil
+		return false, nil
 	}
 
+	// Outside the maintenance windows the patch waits for the next window. A
+	// plan is never applied, so it is computed at any time.
+	if wait := untilNextWindow(r.clock().Now(), r.MaintenanceWindows); wait > 0 && !r.PlanOnly {
+		logger.Info("outside maintenance window, deferring update", "namespace", namespace.Name, "projectId", projectID, "wait", wait)
+		return false, &windowDeferredError{wait: wait}
+	}
+
+	// An existing project label with a different value means the assignment drifted
+	previousProjectID := namespace.Labels[rancherProjectIDLabel]
+	if !writeLabels {
+		previousProjectID = namespace.Annotations[rancherProjectIDAnnotation]
+	}
+	drifted := previousProjectID != "" && previousProjectID != projectID
+
 	// Create a patch for the namespace
-	patch := client.MergeFrom(namespace.DeepCopy())
+	original := namespace.DeepCopy()
+	patch := client.MergeFrom(original)
 
 	// Add/update labels
-	if namespace.Labels == nil {
-		namespace.Labels = make(map[string]string)
-	}
-	namespace.Labels[rancherProjectIDLabel] = projectID
-	if clusterID != "" {
-		namespace.Labels[rancherClusterIDLabel] = clusterID
+	if writeLabels {
+		if namespace.Labels == nil {
+			namespace.Labels = make(map[string]string)
+		}
+		namespace.Labels[rancherProjectIDLabel] = projectID
+		if clusterID != "" {
+			namespace.Labels[rancherClusterIDLabel] = clusterID
+		}
+		if priorityClass != "" {
+			namespace.Labels[r.PriorityClassLabel] = priorityClass
+		}
+		if projectName != "" {
+			namespace.Labels[projectNameLabel] = projectName
+		}
 	}
 
 	// Add/update annotations
-	if namespace.Annotations == nil {
-		namespace.Annotations = make(map[string]string)
+	if writeAnnotations {
+		if namespace.Annotations == nil {
+			namespace.Annotations = make(map[string]string)
+		}
+		namespace.Annotations[rancherProjectIDAnnotation] = projectID
+		if resolution != "" {
+			namespace.Annotations[r.ResolutionAnnotation] = resolution
+		}
+		if r.AnnotateConfidence && confidence != "" {
+			namespace.Annotations[confidenceAnnotation] = string(confidence)
+		}
+		if source != "" {
+			namespace.Annotations[resolutionSourceAnnotation] = source
+		}
+		if checksum != "" {
+			namespace.Annotations[assignmentChecksumAnnotation] = checksum
+		}
+
+		// Record the change in the bounded assignment history
+		if r.HistoryLimit > 0 && previousProjectID != projectID {
+			history, err := appendAssignmentHistory(namespace.Annotations[assignmentHistoryAnnotation],
+				assignmentHistoryEntry{Time: r.clock().Now().UTC(), From: previousProjectID, To: projectID}, r.HistoryLimit)
+			if err != nil {
+				return false, fmt.Errorf("unable to encode assignment history: %w", err)
+			}
+			namespace.Annotations[assignmentHistoryAnnotation] = history
+		}
+	}
+
+	// The assignment is applied now, so it is no longer pending
+	delete(namespace.Annotations, pendingProjectAnnotation)
+
+	// A plan reports the full change and stops before anything is written
+	if r.PlanOnly {
+		r.recordDryRunDiff(original, namespace)
+		return false, errPlanned
+	}
+
+	// Patch the project ID alone first so a rejected supplementary key cannot block it
+	var desired *corev1.Namespace
+	if r.SplitPatches {
+		desired = namespace.DeepCopy()
+		coreAssignment(original, desired).DeepCopyInto(namespace)
 	}
-	namespace.Annotations[rancherProjectIDAnnotation] = projectID
+
+	// Detect namespaces whose admission rejects the change before patching for real
+	if r.DryRunPatches {
+		if err := dryRunPatch(ctx, namespaceClient, namespace, patch); err != nil {
+			return false, clusterError(clusterID, "dry-run patch of namespace "+namespace.Name+" failed", err)
+		}
+		r.recordDryRunDiff(original, namespace)
+	}
+
+	// The patch overwrites namespace with the server's response, so keep the intended state
+	expected := namespace.DeepCopy()
 
 	// Apply the patch using the appropriate cluster client
-	if err := namespaceClient.Patch(ctx, namespace, patch); err != nil {
+	if err := r.patchNamespace(ctx, namespaceClient, clusterID, namespace, patch); err != nil {
 		logger.Error(err, "unable to patch namespace", "namespace", namespace.Name, "clusterId", clusterID)
-		return err
+		return false, clusterError(clusterID, "unable to patch namespace "+namespace.Name, err)
 	}
 
-	return nil
+	// Some webhooks silently drop labels, so confirm the assignment stuck
+	if r.VerifyPatches {
+		if err := r.verifyPatch(ctx, namespaceClient, expected); err != nil {
+			if isNotPersisted(err) {
+				r.eventf(namespace, corev1.EventTypeWarning, "AssignmentNotPersisted",
+					"Project %s was patched but %v", projectID, err)
+			}
+			return false, clusterError(clusterID, "unable to verify patch of namespace "+namespace.Name, err)
+		}
+	}
+
+	if desired != nil {
+		r.applySupplementary(ctx, namespaceClient, namespace, desired, clusterID)
+	}
+
+	if drifted {
+		logger.Info("corrected drifted project assignment", "namespace", namespace.Name, "previousProjectId", previousProjectID, "projectId", projectID, "clusterId", clusterID)
+		assignmentsCorrected.WithLabelValues(clusterID).Inc()
+		r.eventf(namespace, corev1.EventTypeNormal, "DriftCorrected",
+			"Project assignment corrected from %s to %s", previousProjectID, projectID)
+	}
+
+	return true, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -432,17 +1579,80 @@ func (r *NamespaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Manager = mgr
 	r.clusterClients = make(map[string]client.Client)
 	r.lastClusterRefresh = time.Time{}
+	if !r.Once {
+		r.clusterAvailable = make(chan event.GenericEvent, clusterAvailableBuffer)
+	}
 
-	// Start background goroutine to refresh cluster clients
 	ctx := context.Background()
-	go r.refreshClusterClients(ctx)
+
+	if r.SingleCluster != "" {
+		// Cluster discovery is disabled; only build a client for the pinned cluster
+		if r.SingleCluster != "local" {
+			clusterClient, err := r.createClusterClient(ctx, r.SingleCluster)
+			if err != nil {
+				return err
+			}
+			r.clusterClients[r.SingleCluster] = clusterClient
+		}
+	} else if !r.Once {
+		// Refresh cluster clients for as long as the manager runs
+		if err := mgr.Add(clusterRefresher{reconciler: r}); err != nil {
+			return err
+		}
+	}
+
+	// Populate the project cache before the first reconcile. The manager's cache
+	// is not started yet, so read directly from the API server.
+	if r.ProjectCacheTTL > 0 && r.ProjectLister == nil {
+		r.warmProjectCache(ctx, mgr.GetAPIReader())
+
+		// Periodically rebuild the cache in full, independent of its TTL
+		if r.ProjectCacheRebuildInterval > 0 {
+			if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+				return r.rebuildProjectCache(ctx, mgr.GetAPIReader(), r.ProjectCacheRebuildInterval)
+			})); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Decisions reach the status ConfigMap in batches
+	if r.StatusConfigMap != "" {
+		if err := mgr.Add(manager.RunnableFunc(r.runStatusFlusher)); err != nil {
+			return err
+		}
+	}
+
+	// A single pass is driven by the OnceRunner instead of the controller
+	if r.Once {
+		return nil
+	}
 
 	// Set up controller for management cluster namespaces
 	// Note: For downstream clusters, we'll need to access them via Rancher's cluster proxy
 	// The reconcile function will determine which cluster a namespace belongs to
 	// and use the appropriate client
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Namespace{})
+		For(&corev1.Namespace{}).
+		WatchesRawSource(&source.Channel{Source: r.clusterAvailable}, &handler.EnqueueRequestForObject{})
+
+	// Re-evaluate pending namespaces when a project gains the required label
+	if r.ProjectRequiredLabel != nil {
+		project := &unstructured.Unstructured{}
+		project.SetAPIVersion(rancherProjectAPIVersion)
+		project.SetKind(rancherProjectKind)
+		builder = builder.Watches(project,
+			handler.EnqueueRequestsFromMapFunc(r.namespacesForProject),
+			ctrlbuilder.WithPredicates(r.projectBecameEligible()))
+	}
+
+	// Re-evaluate namespaces whose owner mapping changed
+	if r.OwnerMappings {
+		mapping := &unstructured.Unstructured{}
+		mapping.SetAPIVersion(ownerMappingAPIVersion)
+		mapping.SetKind(ownerMappingKind)
+		builder = builder.Watches(mapping, handler.EnqueueRequestsFromMapFunc(r.namespacesForOwnerMapping))
+	}
 
 	return builder.Complete(r)
 }
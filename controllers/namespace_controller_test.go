@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcilePatchesDownstreamNamespaceThroughDownstreamClient(t *testing.T) {
+	ctx := context.Background()
+
+	// The management cluster holds the project and a namespace of the same name
+	// pinned to the downstream cluster
+	management := newNamespace("payments", nil)
+	management.Annotations = map[string]string{viaClusterAnnotation: "c-abc"}
+	r := newTestReconciler(newProject("c-abc", "p-live", "payments"), management)
+
+	downstream := fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).Build()
+	r.clusterClients = map[string]client.Client{"c-abc": downstream}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	patched := &corev1.Namespace{}
+	if err := downstream.Get(ctx, types.NamespacedName{Name: "payments"}, patched); err != nil {
+		t.Fatal(err)
+	}
+	if patched.Labels[rancherProjectIDLabel] != "p-live" || patched.Labels[rancherClusterIDLabel] != "c-abc" {
+		t.Errorf("downstream namespace labels = %v, want the project and cluster labels", patched.Labels)
+	}
+	if patched.Annotations[rancherProjectIDAnnotation] != "p-live" {
+		t.Errorf("downstream namespace annotations = %v, want the project annotation", patched.Annotations)
+	}
+
+	unchanged := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "payments"}, unchanged); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := unchanged.Labels[rancherProjectIDLabel]; ok {
+		t.Errorf("management namespace was labeled: %v", unchanged.Labels)
+	}
+	if _, ok := unchanged.Annotations[rancherProjectIDAnnotation]; ok {
+		t.Errorf("management namespace was annotated: %v", unchanged.Annotations)
+	}
+}
+
+func TestReconcileWithoutClusterClientLeavesManagementNamespace(t *testing.T) {
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+
+	routed := newNamespace("payments", map[string]string{appOwnerLabel: "payments"})
+	routed.Annotations = map[string]string{viaClusterAnnotation: "c-abc"}
+	pinned := newTestReconciler(newProject("c-abc", "p-live", "payments"), newNamespace("payments", map[string]string{appOwnerLabel: "payments"}))
+	pinned.SingleCluster = "c-abc"
+
+	for name, r := range map[string]*NamespaceReconciler{
+		"via-cluster annotation": newTestReconciler(newProject("c-abc", "p-live", "payments"), routed),
+		"single cluster":         pinned,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// The cluster's client has not been created yet
+			if _, err := r.Reconcile(ctx, req); err == nil {
+				t.Fatal("Reconcile() succeeded, want an error until the cluster has a client")
+			}
+
+			namespace := &corev1.Namespace{}
+			if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := namespace.Labels[rancherProjectIDLabel]; ok {
+				t.Errorf("management namespace was assigned through the fallback client: %v", namespace.Labels)
+			}
+		})
+	}
+}
+
+func TestReconcileAssignsLocalNamespaceToDownstreamProject(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("c-abc", "p-live", "payments"),
+		newProject("c-def", "p-other", "orders"),
+		newNamespace("payments", map[string]string{appOwnerLabel: "payments"}),
+	)
+
+	// Namespaces on local see the projects of every cluster
+	projects, _, err := r.listProjects(ctx, "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 {
+		t.Errorf("listProjects(local) = %d projects, want all 2", len(projects))
+	}
+	// Downstream namespaces only see the projects of their own cluster
+	if projects, _, _ = r.listProjects(ctx, "c-def"); len(projects) != 1 || projects[0].GetName() != "p-other" {
+		t.Errorf("listProjects(c-def) = %v, want only p-other", projects)
+	}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "payments"}, namespace); err != nil {
+		t.Fatal(err)
+	}
+	// The cluster comes from the namespace Rancher stores the project in
+	if namespace.Labels[rancherProjectIDLabel] != "p-live" || namespace.Labels[rancherClusterIDLabel] != "c-abc" {
+		t.Errorf("namespace labels = %v, want p-live on cluster c-abc", namespace.Labels)
+	}
+}
+
+func TestGetClusterClientHonorsViaClusterAnnotation(t *testing.T) {
+	pinned := func(name, cluster string) *corev1.Namespace {
+		namespace := newNamespace(name, nil)
+		namespace.Annotations = map[string]string{viaClusterAnnotation: cluster}
+		return namespace
+	}
+	r := newTestReconciler(
+		pinned("to-downstream", "c-abc"),
+		pinned("to-local", "local"),
+		pinned("to-missing", "c-gone"),
+		newNamespace("unpinned", nil),
+	)
+	downstream := fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+	other := fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+	r.clusterClients = map[string]client.Client{"c-abc": downstream, "c-def": other}
+	// The annotation must win over single-cluster routing
+	r.SingleCluster = "c-def"
+
+	tests := []struct {
+		namespace   string
+		wantCluster string
+		wantClient  client.Client
+	}{
+		{namespace: "to-downstream", wantCluster: "c-abc", wantClient: downstream},
+		{namespace: "to-local", wantCluster: "local", wantClient: r.Client},
+		{namespace: "to-missing", wantCluster: "c-gone", wantClient: nil},
+		{namespace: "unpinned", wantCluster: "c-def", wantClient: other},
+	}
+	for _, tt := range tests {
+		clusterID, namespaceClient := r.getClusterClient(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: tt.namespace}})
+		if clusterID != tt.wantCluster || namespaceClient != tt.wantClient {
+			t.Errorf("getClusterClient(%s) = (%s, %p), want (%s, %p)", tt.namespace, clusterID, namespaceClient, tt.wantCluster, tt.wantClient)
+		}
+	}
+}
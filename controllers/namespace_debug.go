@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// NamespaceStatePath is the prefix of the debug endpoint reporting the
+// operator's view of a single namespace, for example /namespaces/payments
+const NamespaceStatePath = "/namespaces/"
+
+// namespaceRecord is what the operator remembers about a namespace between reconciles
+type namespaceRecord struct {
+	lastDecision Decision
+	attempts     int
+}
+
+// namespaceRecords keeps the last decision and reconcile count per namespace
+type namespaceRecords struct {
+	mutex   sync.Mutex
+	records map[string]namespaceRecord
+}
+
+// observe records a finished reconcile, forgetting namespaces that were deleted
+func (n *namespaceRecords) observe(decision Decision) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	// Key by the namespace's own cluster, which lookups and deletions use
+	clusterID, namespace := decision.namespaceKey()
+	key := clusterID + "/" + namespace
+	if decision.Reason == ReasonNamespaceNotFound {
+		delete(n.records, key)
+		return
+	}
+	if n.records == nil {
+		n.records = make(map[string]namespaceRecord)
+	}
+	record := n.records[key]
+	record.lastDecision = decision
+	record.attempts++
+	n.records[key] = record
+}
+
+func (n *namespaceRecords) get(clusterID, namespace string) (namespaceRecord, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	record, ok := n.records[clusterID+"/"+namespace]
+	return record, ok
+}
+
+// NamespaceState is the operator's view of a namespace served by NamespaceStateHandler
+type NamespaceState struct {
+	Namespace string `json:"namespace"`
+	// ClusterID is the cluster the namespace is routed to
+	ClusterID string `json:"clusterId"`
+	// Owner and ResolvedFrom come from a live resolve of the namespace
+	Owner        string `json:"owner,omitempty"`
+	ResolvedFrom string `json:"resolvedFrom,omitempty"`
+	// Skip is the reason a reconcile would skip the namespace before matching a project
+	Skip ReconcileReason `json:"skip,omitempty"`
+	// ProjectID is the project a live resolve picks, the same as Reconcile would
+	ProjectID        string `json:"projectId,omitempty"`
+	ProjectClusterID string `json:"projectClusterId,omitempty"`
+	ResolveError     string `json:"resolveError,omitempty"`
+	// AssignedProjectID is the projectId currently on the namespace
+	AssignedProjectID string `json:"assignedProjectId,omitempty"`
+	// LastDecision is nil until the namespace was reconciled by this process
+	LastDecision *Decision `json:"lastDecision,omitempty"`
+	// Attempts counts reconciles of the namespace since the operator started
+	Attempts int `json:"attempts"`
+	// PatchFailures counts consecutive failed patches towards quarantine
+	PatchFailures int `json:"patchFailures"`
+}
+
+// NamespaceStateHandler serves GET /namespaces/{name} from the reconciler's
+// in-memory state and a live run of the resolution chain. It never patches.
+// It is served behind an AuthorizingHandler.
+type NamespaceStateHandler struct {
+	Reconciler *NamespaceReconciler
+}
+
+// ServeHTTP implements http.Handler
+func (h *NamespaceStateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(req.URL.Path, NamespaceStatePath)
+	if name == "" || strings.Contains(name, "/") {
+		http.Error(w, "expected "+NamespaceStatePath+"{name}", http.StatusNotFound)
+		return
+	}
+
+	r := h.Reconciler
+	ctx := req.Context()
+	clusterID, namespaceClient := r.getClusterClient(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+	if namespaceClient == nil {
+		http.Error(w, "no client available for cluster "+clusterID, http.StatusServiceUnavailable)
+		return
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := namespaceClient.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	state := NamespaceState{
+		Namespace:         name,
+		ClusterID:         clusterID,
+		AssignedProjectID: namespace.Labels[rancherProjectIDLabel],
+		PatchFailures:     r.patchFailures.count(clusterID, name),
+	}
+	if record, ok := r.namespaceRecords.get(clusterID, name); ok {
+		decision := record.lastDecision
+		state.LastDecision = &decision
+		state.Attempts = record.attempts
+	}
+
+	res, err := r.resolveNamespace(ctx, namespaceClient, namespace, clusterID)
+	state.Owner, state.Skip = res.Owner, res.Skip
+	if res.Ref != nil {
+		state.ResolvedFrom = res.From
+		state.ProjectID, state.ProjectClusterID = res.Ref.ProjectID, res.Ref.ClusterID
+	}
+	switch {
+	case err != nil:
+		state.ResolveError = err.Error()
+	case res.Ref == nil && res.Ineligible != nil:
+		state.ResolveError = res.Ineligible.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(state)
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestNamespaceStateHandler(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("local", "p-payments", "payments"),
+		newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+		newNamespace("ledger", map[string]string{appOwnerLabel: "payments"}),
+	)
+	handler := &NamespaceStateHandler{Reconciler: r}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	get := func(path string) (*httptest.ResponseRecorder, NamespaceState) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var state NamespaceState
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+				t.Fatalf("decode %s: %v", path, err)
+			}
+		}
+		return rec, state
+	}
+
+	// A reconciled namespace reports its last decision next to a live resolve
+	rec, state := get(NamespaceStatePath + "checkout")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET checkout status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if state.AssignedProjectID != "p-payments" || state.ProjectID != "p-payments" || state.Owner != "payments" {
+		t.Errorf("checkout state = %+v, want owner payments assigned to and resolving p-payments", state)
+	}
+	if state.Attempts != 1 || state.LastDecision == nil || state.LastDecision.Reason != ReasonAssigned {
+		t.Errorf("checkout attempts = %d, last decision = %+v, want one Assigned reconcile", state.Attempts, state.LastDecision)
+	}
+
+	// A namespace not reconciled yet shows what a reconcile would do, without patching it
+	rec, state = get(NamespaceStatePath + "ledger")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET ledger status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if state.ProjectID != "p-payments" || state.AssignedProjectID != "" || state.LastDecision != nil || state.Attempts != 0 {
+		t.Errorf("ledger state = %+v, want an unassigned namespace resolving p-payments", state)
+	}
+	ledger := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "ledger"}, ledger); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if _, ok := ledger.Labels[rancherProjectIDLabel]; ok {
+		t.Error("debug endpoint patched the namespace")
+	}
+
+	for _, tt := range []struct {
+		method, path string
+		want         int
+	}{
+		{method: http.MethodGet, path: NamespaceStatePath + "missing", want: http.StatusNotFound},
+		{method: http.MethodGet, path: NamespaceStatePath, want: http.StatusNotFound},
+		{method: http.MethodGet, path: NamespaceStatePath + "checkout/extra", want: http.StatusNotFound},
+		{method: http.MethodPost, path: NamespaceStatePath + "checkout", want: http.StatusMethodNotAllowed},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+	}
+}
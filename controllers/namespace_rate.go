@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// namespaceRateLimits holds a token bucket per namespace so a namespace that
+// another controller edits rapidly cannot monopolize a worker
+type namespaceRateLimits struct {
+	mutex    sync.Mutex
+	limiters map[string]flowcontrol.RateLimiter
+}
+
+// allow takes a token for the namespace and returns how long to wait before
+// retrying when none is left
+func (n *namespaceRateLimits) allow(key string, qps float32, burst int) (bool, time.Duration) {
+	if qps <= 0 {
+		return true, 0
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.limiters == nil {
+		n.limiters = make(map[string]flowcontrol.RateLimiter)
+	}
+	limiter, ok := n.limiters[key]
+	if !ok {
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+		n.limiters[key] = limiter
+	}
+	if limiter.TryAccept() {
+		return true, 0
+	}
+	return false, time.Duration(float64(time.Second) / float64(qps))
+}
+
+// forget drops the bucket of a deleted namespace
+func (n *namespaceRateLimits) forget(key string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.limiters, key)
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileThrottlesBusyNamespace(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("local", "p-payments", "payments"),
+		newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+		newNamespace("ledger", map[string]string{appOwnerLabel: "payments"}),
+	)
+	// Slow enough that no token is refilled while the test runs
+	r.NamespaceQPS = 0.5
+	r.NamespaceBurst = 2
+	reconcile := func(name string) time.Duration {
+		t.Helper()
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+		if err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+		return result.RequeueAfter
+	}
+
+	for i := 0; i < r.NamespaceBurst; i++ {
+		if wait := reconcile("checkout"); wait != 0 {
+			t.Fatalf("reconcile %d within the burst requeued after %s", i+1, wait)
+		}
+	}
+	if wait := reconcile("checkout"); wait != 2*time.Second {
+		t.Errorf("RequeueAfter beyond the burst = %s, want 2s", wait)
+	}
+
+	// Other namespaces have their own bucket
+	if wait := reconcile("ledger"); wait != 0 {
+		t.Errorf("ledger throttled by checkout's reconciles, RequeueAfter = %s", wait)
+	}
+}
+
+func TestNamespaceRateLimitsDisabled(t *testing.T) {
+	var limits namespaceRateLimits
+	for i := 0; i < 100; i++ {
+		if allowed, wait := limits.allow("local/checkout", 0, 0); !allowed || wait != 0 {
+			t.Fatalf("allow() with no QPS = (%v, %s), want unlimited", allowed, wait)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namespaceResolution is the outcome of the resolution chain for one namespace
+type namespaceResolution struct {
+	// Owner is the owner value the project was resolved for, after transforms
+	Owner string
+	// Ref is the resolved project, nil when nothing matched
+	Ref *ProjectRef
+	// From names the source that produced Ref, for logging
+	From string
+	// Behavior is the resolution behavior selected for the namespace
+	Behavior Behavior
+	// Skip is set when the namespace has no owner to resolve
+	Skip ReconcileReason
+	// SkipErr explains an owner disagreement skip
+	SkipErr error
+	// Ineligible is the error of a matching project that is not eligible yet
+	Ineligible error
+}
+
+// resolveNamespace runs the full resolution chain Reconcile uses: owner
+// mappings, owning apps, service accounts, cost centers and the project pool
+// first, then resolution profiles or the namespace owner. It never patches,
+// so the webhook and the debug endpoint use it to report what Reconcile would do.
+func (r *NamespaceReconciler) resolveNamespace(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, clusterID string) (namespaceResolution, error) {
+	res := namespaceResolution{Behavior: r.behaviorFor(namespace)}
+
+	// Each source is consulted in order until one resolves
+	sources := []struct {
+		enabled bool
+		from    string
+		resolve func() (string, *ProjectRef, error)
+	}{
+		// OwnerMapping resources override every other resolution source
+		{r.OwnerMappings, "owner mapping", func() (string, *ProjectRef, error) {
+			return r.resolveOwnerMapping(ctx, namespaceClient, namespace, clusterID)
+		}},
+		// Namespaces created by a Rancher App belong to the app's project
+		{r.ResolveOwnerApps, "owning app", func() (string, *ProjectRef, error) {
+			return r.resolveOwnerApp(ctx, namespace)
+		}},
+		// Namespaces created by a mapped service account belong to its project
+		{r.ServiceAccountAnnotation != "", "service account", func() (string, *ProjectRef, error) {
+			return r.resolveServiceAccount(ctx, namespace, clusterID)
+		}},
+		// Finance driven assignment maps cost centers to projects
+		{r.CostCenterLabel != "", "cost center", func() (string, *ProjectRef, error) {
+			return r.resolveCostCenter(ctx, namespace, clusterID)
+		}},
+		// Synthetic fleets spread namespaces evenly across the pool
+		{len(r.ProjectPool) > 0, "project pool", func() (string, *ProjectRef, error) {
+			return r.resolveProjectPool(ctx, namespace, clusterID)
+		}},
+	}
+	for _, source := range sources {
+		if !source.enabled {
+			continue
+		}
+		owner, ref, err := source.resolve()
+		if isProjectIneligible(err) {
+			res.Ineligible, err = err, nil
+		}
+		if err != nil {
+			return res, fmt.Errorf("unable to resolve %s project: %w", source.from, err)
+		}
+		if ref != nil {
+			res.Owner, res.Ref, res.From = owner, ref, source.from
+			return res, nil
+		}
+	}
+
+	if len(r.ResolutionProfiles) > 0 {
+		// Resolution profiles bring their own owner labels and project filters
+		owner, ref, err := r.resolveWithProfiles(ctx, namespace, clusterID, res.Behavior)
+		res.Owner, res.Ref, res.From = owner, ref, "resolution profile"
+		if isProjectIneligible(err) {
+			res.Ineligible, err = err, nil
+		}
+		if err != nil {
+			return res, fmt.Errorf("unable to resolve project with profiles: %w", err)
+		}
+		if owner == "" {
+			res.Skip = ReasonNoOwnerLabel
+		}
+		return res, nil
+	}
+
+	owner, ownerSource, err := r.namespaceOwner(ctx, namespaceClient, namespace, clusterID)
+	if isOwnerDisagreement(err) && r.OwnerLabelPolicy == OwnerLabelPolicyRequireAgreement {
+		res.Skip, res.SkipErr = ReasonOwnerDisagreement, err
+		return res, nil
+	}
+	if err != nil {
+		return res, fmt.Errorf("unable to determine namespace owner: %w", err)
+	}
+	res.Owner = owner
+	if owner == "" {
+		// Resolvers matching the namespace itself do not need an owner
+		if r.resolvesWithoutOwner(res.Behavior) {
+			ref, err := r.resolveProject(ctx, namespace, "", clusterID, res.Behavior)
+			if isProjectIneligible(err) {
+				res.Ineligible, err = err, nil
+			}
+			if err != nil {
+				return res, fmt.Errorf("unable to resolve project for namespace without owner: %w", err)
+			}
+			if ref != nil || res.Ineligible != nil {
+				res.Ref, res.From = ref, string(SourceResolver)
+				return res, nil
+			}
+		}
+		res.Skip = ReasonNoOwnerLabel
+		return res, nil
+	}
+
+	// Normalize the owner value through the configured transform pipeline
+	if res.Owner = r.normalizeOwner(owner, res.Behavior); res.Owner == "" {
+		res.Owner = owner
+		res.Skip = ReasonOwnerEmpty
+		return res, nil
+	}
+
+	// Resolve the project for the owner, consulting the external resolver first when configured
+	ref, err := r.resolveProject(ctx, namespace, res.Owner, clusterID, res.Behavior)
+	if isProjectIneligible(err) {
+		res.Ineligible, err = err, nil
+	}
+	if err != nil {
+		return res, fmt.Errorf("unable to find project for owner %s: %w", res.Owner, err)
+	}
+	if ref != nil && ref.Source == "" {
+		ref.Source = ownerSource
+	}
+	res.Ref, res.From = ref, string(ownerSource)
+	return res, nil
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Namespace lifecycle transitions detected between reconciles
+const (
+	transitionNone = iota
+	// transitionRecreated means the namespace name now belongs to a new UID
+	transitionRecreated
+	// transitionLeftTerminating means a terminating namespace became active
+	// again, which Kubernetes should never do
+	transitionLeftTerminating
+)
+
+// namespaceStates remembers the UID and phase each namespace had when it was
+// last reconciled, so recreation can reset state cached for the old object
+type namespaceStates struct {
+	mutex  sync.Mutex
+	states map[string]namespaceState
+}
+
+type namespaceState struct {
+	uid         types.UID
+	terminating bool
+}
+
+// observe records the namespace and returns the transition since the previous observation
+func (s *namespaceStates) observe(clusterID string, namespace *corev1.Namespace) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.states == nil {
+		s.states = make(map[string]namespaceState)
+	}
+	key := clusterID + "/" + namespace.Name
+	current := namespaceState{uid: namespace.UID, terminating: namespaceTerminating(namespace)}
+	previous, seen := s.states[key]
+	s.states[key] = current
+
+	switch {
+	case !seen:
+		return transitionNone
+	case previous.uid != current.uid:
+		return transitionRecreated
+	case previous.terminating && !current.terminating:
+		return transitionLeftTerminating
+	default:
+		return transitionNone
+	}
+}
+
+// forget drops the state of a deleted namespace
+func (s *namespaceStates) forget(clusterID, namespace string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.states, clusterID+"/"+namespace)
+}
+
+// namespaceTerminating reports whether the namespace is being deleted
+func namespaceTerminating(namespace *corev1.Namespace) bool {
+	return namespace.DeletionTimestamp != nil || namespace.Status.Phase == corev1.NamespaceTerminating
+}
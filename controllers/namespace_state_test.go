@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestNamespaceStatesObserve(t *testing.T) {
+	namespace := func(uid types.UID, phase corev1.NamespacePhase) *corev1.Namespace {
+		ns := newNamespace("checkout", nil)
+		ns.UID = uid
+		ns.Status.Phase = phase
+		return ns
+	}
+	steps := []struct {
+		name      string
+		namespace *corev1.Namespace
+		want      int
+	}{
+		{name: "first sighting", namespace: namespace("uid-1", corev1.NamespaceActive), want: transitionNone},
+		{name: "same object", namespace: namespace("uid-1", corev1.NamespaceActive), want: transitionNone},
+		{name: "new uid", namespace: namespace("uid-2", corev1.NamespaceActive), want: transitionRecreated},
+		{name: "starts terminating", namespace: namespace("uid-2", corev1.NamespaceTerminating), want: transitionNone},
+		{name: "leaves terminating", namespace: namespace("uid-2", corev1.NamespaceActive), want: transitionLeftTerminating},
+	}
+
+	var states namespaceStates
+	for _, step := range steps {
+		if got := states.observe("local", step.namespace); got != step.want {
+			t.Errorf("%s: observe() = %d, want %d", step.name, got, step.want)
+		}
+	}
+
+	// Other clusters track the same name independently
+	if got := states.observe("c-other", namespace("uid-9", corev1.NamespaceActive)); got != transitionNone {
+		t.Errorf("observe() on another cluster = %d, want %d", got, transitionNone)
+	}
+
+	states.forget("local", "checkout")
+	if got := states.observe("local", namespace("uid-3", corev1.NamespaceActive)); got != transitionNone {
+		t.Errorf("observe() after forget = %d, want %d", got, transitionNone)
+	}
+}
+
+func TestReconcileResetsStateOfRecreatedNamespace(t *testing.T) {
+	ctx := context.Background()
+	old := newNamespace("checkout", nil)
+	old.UID = "uid-old"
+	r := newTestReconciler(newProject("local", "p-payments", "payments"), old)
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}
+
+	// Observe the original object
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("first Reconcile() error = %v", err)
+	}
+
+	if err := r.Delete(ctx, old); err != nil {
+		t.Fatalf("delete namespace: %v", err)
+	}
+	recreated := newNamespace("checkout", map[string]string{appOwnerLabel: "payments"})
+	recreated.UID = "uid-new"
+	if err := r.Create(ctx, recreated); err != nil {
+		t.Fatalf("create namespace: %v", err)
+	}
+	// A patch recorded against the old object happens to share the new
+	// object's resourceVersion; it must not be taken for a stale cache read
+	r.patched.record("local", "checkout", recreated.ResourceVersion, r.clock().Now())
+
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("second Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("RequeueAfter = %v, want immediate assignment", result.RequeueAfter)
+	}
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if got.Labels[rancherProjectIDLabel] != "p-payments" {
+		t.Errorf("project label = %q, want p-payments", got.Labels[rancherProjectIDLabel])
+	}
+	close(recorder.Events)
+	for event := range recorder.Events {
+		if strings.Contains(event, "UnexpectedPhaseTransition") {
+			t.Errorf("unexpected event %q", event)
+		}
+	}
+}
+
+func TestReconcileSkipsTerminatingNamespace(t *testing.T) {
+	ctx := context.Background()
+	ns := newNamespace("checkout", map[string]string{appOwnerLabel: "payments"})
+	ns.Status.Phase = corev1.NamespaceTerminating
+	r := newTestReconciler(newProject("local", "p-payments", "payments"), ns)
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if _, ok := got.Labels[rancherProjectIDLabel]; ok {
+		t.Errorf("terminating namespace was assigned to %q", got.Labels[rancherProjectIDLabel])
+	}
+}
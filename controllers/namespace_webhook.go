@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// NamespaceWebhookPath is the path the namespace validating webhook is served on
+const NamespaceWebhookPath = "/validate-v1-namespace"
+
+// NamespaceValidator is a validating admission handler for namespaces. It never
+// denies requests; it only returns warnings about the project the namespace
+// resolves to through the same resolution chain the controller uses.
+type NamespaceValidator struct {
+	Reconciler *NamespaceReconciler
+	Decoder    *admission.Decoder
+}
+
+// Handle implements admission.Handler
+func (v *NamespaceValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	logger := log.FromContext(ctx)
+
+	namespace := &corev1.Namespace{}
+	if err := v.Decoder.Decode(req, namespace); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// The webhook is served on the management cluster
+	res, err := v.Reconciler.resolveNamespace(ctx, v.Reconciler.Client, namespace, "local")
+	if err != nil {
+		// Resolution problems are reported by the controller, never block admission
+		logger.V(1).Info("unable to resolve project during admission", "namespace", namespace.Name, "owner", res.Owner, "error", err)
+		return admission.Allowed("")
+	}
+
+	if ref := res.Ref; ref != nil && ref.Project != nil && projectTerminating(ref.Project) {
+		return admission.Allowed("").WithWarnings(fmt.Sprintf(
+			"project %s for owner %q is terminating; namespace %s will not be assigned to it",
+			ref.ProjectID, res.Owner, namespace.Name))
+	}
+
+	return admission.Allowed("")
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func namespaceAdmissionRequest(t *testing.T, namespace *corev1.Namespace) admission.Request {
+	t.Helper()
+	raw, err := json.Marshal(namespace)
+	if err != nil {
+		t.Fatalf("marshal namespace: %v", err)
+	}
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+}
+
+func TestNamespaceValidatorWarnsAboutTerminatingProject(t *testing.T) {
+	terminating := newProject("local", "p-gone", "payments")
+	now := metav1.Now()
+	terminating.SetDeletionTimestamp(&now)
+	terminating.SetFinalizers([]string{"controller.cattle.io/project-precan-alert-controller"})
+
+	tests := []struct {
+		name        string
+		project     string
+		wantWarning bool
+	}{
+		{name: "terminating project", project: "payments", wantWarning: true},
+		{name: "unknown owner", project: "orders", wantWarning: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(terminating)
+			v := &NamespaceValidator{Reconciler: r, Decoder: admission.NewDecoder(r.Scheme)}
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "payments-prod",
+				Labels: map[string]string{appOwnerLabel: tt.project},
+			}}
+
+			resp := v.Handle(context.Background(), namespaceAdmissionRequest(t, namespace))
+
+			// The validator warns but never denies
+			if !resp.Allowed {
+				t.Fatalf("response denied: %v", resp.Result)
+			}
+			if !tt.wantWarning {
+				if len(resp.Warnings) != 0 {
+					t.Errorf("warnings = %v, want none", resp.Warnings)
+				}
+				return
+			}
+			if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "p-gone") || !strings.Contains(resp.Warnings[0], "terminating") {
+				t.Errorf("warnings = %v, want one naming the terminating project", resp.Warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// decisionCollector keeps every decision of a single pass and forwards them
+// to the configured sink
+type decisionCollector struct {
+	mutex     sync.Mutex
+	decisions []Decision
+	next      DecisionSink
+}
+
+func (c *decisionCollector) Record(decision Decision) {
+	c.mutex.Lock()
+	c.decisions = append(c.decisions, decision)
+	c.mutex.Unlock()
+
+	if c.next != nil {
+		c.next.Record(decision)
+	}
+}
+
+// OnceRunner reconciles every namespace a single time and then stops the
+// manager. It replaces the controller when the operator runs with --once.
+type OnceRunner struct {
+	Reconciler *NamespaceReconciler
+	// Stop shuts the manager down once the pass has finished
+	Stop func()
+
+	// Decisions holds the outcome of every namespace after Start returns
+	Decisions []Decision
+}
+
+// Start implements manager.Runnable
+func (o *OnceRunner) Start(ctx context.Context) error {
+	defer o.Stop()
+
+	r := o.Reconciler
+	logger := log.FromContext(ctx).WithName("once")
+
+	if !r.Manager.GetCache().WaitForCacheSync(ctx) {
+		return fmt.Errorf("cache did not sync")
+	}
+	if r.SingleCluster == "" {
+		r.doRefreshClusterClients(ctx)
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		return clusterError("local", "unable to list namespaces", err)
+	}
+
+	collector := &decisionCollector{next: r.DecisionSink}
+	r.DecisionSink = collector
+	defer func() { r.DecisionSink = collector.next }()
+
+	for _, namespace := range namespaces.Items {
+		if ctx.Err() != nil {
+			break
+		}
+		// Failures are recorded as decisions; the pass carries on with the next namespace
+		_, _ = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: namespace.Name}})
+	}
+
+	o.Decisions = collector.decisions
+	logger.Info("single pass finished", "namespaces", len(namespaces.Items))
+	return nil
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// syncedCache reports its informers as synced. Other cache methods are not implemented.
+type syncedCache struct {
+	cache.Cache
+}
+
+func (syncedCache) WaitForCacheSync(context.Context) bool {
+	return true
+}
+
+// onceManager adds a synced cache to fakeManager
+type onceManager struct {
+	fakeManager
+}
+
+func (m *onceManager) GetCache() cache.Cache {
+	return syncedCache{}
+}
+
+// sinkFunc adapts a function to a DecisionSink
+type sinkFunc func(Decision)
+
+func (f sinkFunc) Record(decision Decision) {
+	f(decision)
+}
+
+func TestOnceRunnerReconcilesEveryNamespaceOnce(t *testing.T) {
+	r := newTestReconciler(
+		newProject("local", "p-payments", "payments"),
+		newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+		newNamespace("scratch", nil),
+	)
+	r.Manager = &onceManager{}
+	r.SingleCluster = "local"
+	var forwarded int
+	sink := sinkFunc(func(Decision) { forwarded++ })
+	r.DecisionSink = sink
+	stopped := false
+	runner := &OnceRunner{Reconciler: r, Stop: func() { stopped = true }}
+
+	if err := runner.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if !stopped {
+		t.Error("Start() returned without stopping the manager")
+	}
+	reasons := make(map[string]ReconcileReason)
+	for _, decision := range runner.Decisions {
+		reasons[decision.Namespace] = decision.Reason
+	}
+	want := map[string]ReconcileReason{"checkout": ReasonAssigned, "scratch": ReasonNoOwnerLabel}
+	if len(runner.Decisions) != len(want) {
+		t.Errorf("Decisions = %+v, want one per namespace", runner.Decisions)
+	}
+	for namespace, reason := range want {
+		if reasons[namespace] != reason {
+			t.Errorf("decision for %s = %q, want %q", namespace, reasons[namespace], reason)
+		}
+	}
+	if forwarded != len(want) {
+		t.Errorf("configured sink received %d decisions, want %d", forwarded, len(want))
+	}
+	if _, ok := r.DecisionSink.(sinkFunc); !ok {
+		t.Errorf("DecisionSink = %T after the pass, want the configured sink restored", r.DecisionSink)
+	}
+}
+
+func TestOnceRunnerStopsOnCancelledContext(t *testing.T) {
+	r := newTestReconciler(newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}))
+	r.Manager = &onceManager{}
+	r.SingleCluster = "local"
+	stopped := false
+	runner := &OnceRunner{Reconciler: r, Stop: func() { stopped = true }}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runner.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if len(runner.Decisions) != 0 {
+		t.Errorf("Decisions = %+v, want none after cancellation", runner.Decisions)
+	}
+	if !stopped {
+		t.Error("Start() returned without stopping the manager")
+	}
+}
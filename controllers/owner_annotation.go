@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ownerTemplateData is the data available to the owner annotation template
+type ownerTemplateData struct {
+	// Value is the raw annotation value
+	Value string
+	// Namespace is the namespace name
+	Namespace string
+}
+
+// ParseOwnerTemplate parses the template applied to the owner annotation
+// value, for example "{{ .Value }}" or "team-{{ .Value }}"
+func ParseOwnerTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("owner").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse owner template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// annotationOwner reads the owner from the configured annotation, rendering
+// it through the owner template when one is set
+func (r *NamespaceReconciler) annotationOwner(namespace *corev1.Namespace) (string, error) {
+	if r.OwnerAnnotation == "" {
+		return "", nil
+	}
+
+	value := namespace.Annotations[r.OwnerAnnotation]
+	if value == "" || r.OwnerTemplate == nil {
+		return value, nil
+	}
+
+	var out bytes.Buffer
+	if err := r.OwnerTemplate.Execute(&out, ownerTemplateData{Value: value, Namespace: namespace.Name}); err != nil {
+		return "", fmt.Errorf("unable to render owner template: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
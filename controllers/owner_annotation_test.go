@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileResolvesOwnerFromConfiguredAnnotation(t *testing.T) {
+	ctx := context.Background()
+	namespace := newNamespace("checkout", nil)
+	namespace.Annotations = map[string]string{"example.com/team": "payments"}
+	r := newTestReconciler(newProject("local", "p-team", "team-payments"), namespace)
+	r.OwnerAnnotation = "example.com/team"
+	tmpl, err := ParseOwnerTemplate("team-{{ .Value }}")
+	if err != nil {
+		t.Fatalf("ParseOwnerTemplate() error = %v", err)
+	}
+	r.OwnerTemplate = tmpl
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if got.Labels[rancherProjectIDLabel] != "p-team" {
+		t.Errorf("project label = %q, want p-team from the templated annotation owner", got.Labels[rancherProjectIDLabel])
+	}
+}
+
+func TestAnnotationOwnerTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		value    string
+		want     string
+		wantErr  bool
+	}{
+		{name: "raw value without template", value: "payments", want: "payments"},
+		{name: "value rendered through template", template: "{{ .Namespace }}-{{ .Value }}", value: "payments", want: "checkout-payments"},
+		{name: "missing annotation", template: "team-{{ .Value }}", want: ""},
+		{name: "unknown field", template: "{{ .Team }}", value: "payments", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseOwnerTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseOwnerTemplate() error = %v", err)
+			}
+			r := &NamespaceReconciler{OwnerAnnotation: "example.com/team", OwnerTemplate: tmpl}
+			namespace := newNamespace("checkout", nil)
+			if tt.value != "" {
+				namespace.Annotations = map[string]string{"example.com/team": tt.value}
+			}
+
+			got, err := r.annotationOwner(namespace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("annotationOwner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("annotationOwner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Rancher project-scoped App resource that creates target namespaces
+const (
+	rancherAppAPIVersion = "project.cattle.io/v3"
+	rancherAppKind       = "App"
+)
+
+// resolveOwnerApp follows the namespace's owner reference to the Rancher App
+// that created it and returns the app name and the app's project. It returns
+// a nil reference when the namespace is not owned by an app.
+func (r *NamespaceReconciler) resolveOwnerApp(ctx context.Context, namespace *corev1.Namespace) (string, *ProjectRef, error) {
+	for _, owner := range namespace.OwnerReferences {
+		if owner.APIVersion != rancherAppAPIVersion || owner.Kind != rancherAppKind {
+			continue
+		}
+
+		// Apps live in their project's namespace, which the owner reference does not carry
+		appList := &unstructured.UnstructuredList{}
+		appList.SetGroupVersionKind(schema.GroupVersionKind{Group: "project.cattle.io", Version: "v3", Kind: "AppList"})
+		if err := r.List(ctx, appList, client.MatchingFields{"metadata.name": owner.Name}); err != nil {
+			return "", nil, clusterError("local", "unable to list apps named "+owner.Name, err)
+		}
+
+		for i := range appList.Items {
+			app := &appList.Items[i]
+			if app.GetUID() != owner.UID {
+				continue
+			}
+
+			// spec.projectName has the form <clusterId>:<projectId>
+			projectName, _, _ := unstructured.NestedString(app.Object, "spec", "projectName")
+			projectID := projectName
+			projectClusterID := r.extractClusterID(projectName)
+			if projectClusterID != "" {
+				projectID = projectName[len(projectClusterID)+1:]
+			}
+			if projectID == "" {
+				return owner.Name, nil, nil
+			}
+			return owner.Name, &ProjectRef{ProjectID: projectID, ClusterID: projectClusterID, Confidence: ConfidenceExact, Source: SourceOwnerApp}, nil
+		}
+	}
+
+	return "", nil, nil
+}
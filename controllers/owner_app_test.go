@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newRancherApp(projectNamespace, name, uid, projectName string) *unstructured.Unstructured {
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(schema.GroupVersionKind{Group: "project.cattle.io", Version: "v3", Kind: "App"})
+	app.SetNamespace(projectNamespace)
+	app.SetName(name)
+	app.SetUID(types.UID(uid))
+	_ = unstructured.SetNestedField(app.Object, projectName, "spec", "projectName")
+	return app
+}
+
+func TestReconcileResolvesProjectFromOwningApp(t *testing.T) {
+	ctx := context.Background()
+	// The owner label points elsewhere; the owning app takes precedence
+	namespace := newNamespace("grafana", map[string]string{appOwnerLabel: "payments"})
+	namespace.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: rancherAppAPIVersion, Kind: rancherAppKind, Name: "grafana", UID: "uid-monitoring",
+	}}
+	r := newTestReconciler()
+	// Unstructured reads go to the API server, which supports the metadata.name field selector
+	appIndex := &unstructured.Unstructured{}
+	appIndex.SetGroupVersionKind(schema.GroupVersionKind{Group: "project.cattle.io", Version: "v3", Kind: "App"})
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(
+			newProject("local", "p-payments", "payments"),
+			// An app of the same name in another project must not be followed
+			newRancherApp("p-other", "grafana", "uid-other", "c-abc:p-other"),
+			newRancherApp("p-monitoring", "grafana", "uid-monitoring", "c-abc:p-monitoring"),
+			namespace,
+		).
+		WithIndex(appIndex, "metadata.name", func(obj client.Object) []string {
+			return []string{obj.GetName()}
+		}).Build()
+	r.ResolveOwnerApps = true
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "grafana"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "grafana"}, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if got.Labels[rancherProjectIDLabel] != "p-monitoring" || got.Labels[rancherClusterIDLabel] != "c-abc" {
+		t.Errorf("labels = %v, want the owning app's project p-monitoring on c-abc", got.Labels)
+	}
+}
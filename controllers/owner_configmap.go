@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// configMapOwner reads the owner from the configured key of a ConfigMap inside
+// the namespace, returning an empty string when the ConfigMap or key is missing
+func (r *NamespaceReconciler) configMapOwner(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace) (string, error) {
+	if r.OwnerConfigMap == "" || r.OwnerConfigMapKey == "" {
+		return "", nil
+	}
+
+	// Read the management cluster directly so the manager does not start a
+	// cluster-wide ConfigMap informer
+	var reader client.Reader = namespaceClient
+	if r.Manager != nil && namespaceClient == r.Client {
+		reader = r.Manager.GetAPIReader()
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: r.OwnerConfigMap}, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(configMap.Data[r.OwnerConfigMapKey]), nil
+}
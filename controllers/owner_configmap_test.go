@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func teamInfo(namespace string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "team-info"}, Data: data}
+}
+
+func TestReconcileResolvesOwnerFromNamespaceConfigMap(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("local", "p-live", "payments"),
+		newNamespace("checkout", nil),
+		newNamespace("scratch", nil),
+	)
+	r.OwnerConfigMap = "team-info"
+	r.OwnerConfigMapKey = "owner"
+	// The ConfigMaps are read uncached, straight from the API server
+	apiReader := fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(
+		teamInfo("checkout", map[string]string{"owner": " payments\n"}),
+		teamInfo("scratch", map[string]string{"team": "payments"}),
+	).Build()
+	r.Manager = &fakeManager{reader: apiReader}
+
+	wantProject := map[string]string{"checkout": "p-live", "scratch": ""}
+	for name, want := range wantProject {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			t.Fatalf("get namespace %s: %v", name, err)
+		}
+		if got := namespace.Labels[rancherProjectIDLabel]; got != want {
+			t.Errorf("%s project label = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNamespaceOwnerFallsBackToFleetBundle(t *testing.T) {
+	const bundleLabel = "fleet.cattle.io/bundle-name"
+	const ownerAnnotation = "example.io/owner"
+	tests := []struct {
+		name        string
+		fleetLabel  string
+		labels      map[string]string
+		annotations map[string]string
+		want        string
+		wantSource  ResolutionSource
+	}{
+		{name: "bundle label", fleetLabel: bundleLabel, labels: map[string]string{bundleLabel: "payments"},
+			want: "payments", wantSource: SourceFleet},
+		{name: "owner label wins", fleetLabel: bundleLabel, labels: map[string]string{bundleLabel: "payments", appOwnerLabel: "billing"},
+			want: "billing", wantSource: SourceLabel},
+		{name: "owner annotation wins", fleetLabel: bundleLabel, labels: map[string]string{bundleLabel: "payments"},
+			annotations: map[string]string{ownerAnnotation: "billing"}, want: "billing", wantSource: SourceAnnotation},
+		{name: "not configured", labels: map[string]string{bundleLabel: "payments"}},
+		{name: "empty bundle label", fleetLabel: bundleLabel, labels: map[string]string{bundleLabel: ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := newNamespace("checkout", tt.labels)
+			namespace.Annotations = tt.annotations
+			r := newTestReconciler(namespace)
+			r.FleetOwnerLabel = tt.fleetLabel
+			r.OwnerAnnotation = ownerAnnotation
+
+			owner, source, err := r.namespaceOwner(context.Background(), r.Client, namespace, "local")
+			if err != nil {
+				t.Fatalf("namespaceOwner() error = %v", err)
+			}
+			if owner != tt.want || source != tt.wantSource {
+				t.Errorf("namespaceOwner() = (%q, %q), want (%q, %q)", owner, source, tt.want, tt.wantSource)
+			}
+		})
+	}
+}
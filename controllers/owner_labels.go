@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// OwnerLabelPolicy decides which owner is used when several configured owner
+// label keys are present with different values
+type OwnerLabelPolicy string
+
+const (
+	// OwnerLabelPolicyFirstWins uses the value of the first key present
+	OwnerLabelPolicyFirstWins OwnerLabelPolicy = "first-wins"
+	// OwnerLabelPolicyError fails the reconcile so it is retried and surfaced as an error
+	OwnerLabelPolicyError OwnerLabelPolicy = "error"
+	// OwnerLabelPolicyRequireAgreement skips the namespace until the labels agree
+	OwnerLabelPolicyRequireAgreement OwnerLabelPolicy = "require-agreement"
+)
+
+// ParseOwnerLabelPolicy validates a policy name supplied on the command line
+func ParseOwnerLabelPolicy(value string) (OwnerLabelPolicy, error) {
+	switch policy := OwnerLabelPolicy(value); policy {
+	case OwnerLabelPolicyFirstWins, OwnerLabelPolicyError, OwnerLabelPolicyRequireAgreement:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown owner label policy %q", value)
+	}
+}
+
+// ownerDisagreementError reports owner labels with different values
+type ownerDisagreementError struct {
+	values map[string]string
+}
+
+func (e *ownerDisagreementError) Error() string {
+	pairs := make([]string, 0, len(e.values))
+	for key, value := range e.values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return fmt.Sprintf("owner labels disagree: %s", strings.Join(pairs, ", "))
+}
+
+// isOwnerDisagreement reports whether err marks disagreeing owner labels
+func isOwnerDisagreement(err error) bool {
+	var disagreement *ownerDisagreementError
+	return errors.As(err, &disagreement)
+}
+
+// labelOwner returns the owner from the configured owner label keys, which
+// default to appOwner. Disagreeing values are reported as an error unless
+// the first-wins policy is used.
+func (r *NamespaceReconciler) labelOwner(namespace *corev1.Namespace) (string, error) {
+	keys := r.OwnerLabels
+	if len(keys) == 0 {
+		return namespace.Labels[appOwnerLabel], nil
+	}
+
+	owner := ""
+	values := make(map[string]string)
+	for _, key := range keys {
+		value := namespace.Labels[key]
+		if value == "" {
+			continue
+		}
+		if owner == "" {
+			owner = value
+			if r.OwnerLabelPolicy == OwnerLabelPolicyFirstWins || r.OwnerLabelPolicy == "" {
+				return owner, nil
+			}
+		}
+		values[key] = value
+	}
+
+	for _, value := range values {
+		if value != owner {
+			return "", &ownerDisagreementError{values: values}
+		}
+	}
+	return owner, nil
+}
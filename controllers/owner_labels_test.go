@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileOwnerLabelPolicies(t *testing.T) {
+	disagreeing := map[string]string{"team": "payments", "owner": "billing"}
+	tests := []struct {
+		name      string
+		policy    OwnerLabelPolicy
+		labels    map[string]string
+		want      string
+		wantErr   bool
+		wantEvent bool
+	}{
+		{name: "default uses first key", labels: disagreeing, want: "p-payments"},
+		{name: "first wins", policy: OwnerLabelPolicyFirstWins, labels: disagreeing, want: "p-payments"},
+		{name: "error", policy: OwnerLabelPolicyError, labels: disagreeing, wantErr: true},
+		{name: "require agreement skips", policy: OwnerLabelPolicyRequireAgreement, labels: disagreeing, wantEvent: true},
+		{name: "require agreement with agreeing labels", policy: OwnerLabelPolicyRequireAgreement,
+			labels: map[string]string{"team": "billing", "owner": "billing"}, want: "p-billing"},
+		{name: "first key missing", policy: OwnerLabelPolicyRequireAgreement,
+			labels: map[string]string{"owner": "billing"}, want: "p-billing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(
+				newProject("local", "p-payments", "payments"),
+				newProject("local", "p-billing", "billing"),
+				newNamespace("checkout", tt.labels),
+			)
+			r.OwnerLabels = []string{"team", "owner"}
+			r.OwnerLabelPolicy = tt.policy
+			recorder := record.NewFakeRecorder(10)
+			r.Recorder = recorder
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != tt.want {
+				t.Errorf("project label = %q, want %q", got.Labels[rancherProjectIDLabel], tt.want)
+			}
+			var disagreements int
+			for len(recorder.Events) > 0 {
+				if strings.HasPrefix(<-recorder.Events, corev1.EventTypeWarning+" "+string(ReasonOwnerDisagreement)+" owner labels disagree: owner=billing, team=payments") {
+					disagreements++
+				}
+			}
+			if (disagreements == 1) != tt.wantEvent || disagreements > 1 {
+				t.Errorf("OwnerDisagreement events = %d, want event %v", disagreements, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestParseOwnerLabelPolicy(t *testing.T) {
+	for _, value := range []string{"first-wins", "error", "require-agreement"} {
+		if policy, err := ParseOwnerLabelPolicy(value); err != nil || string(policy) != value {
+			t.Errorf("ParseOwnerLabelPolicy(%q) = (%q, %v)", value, policy, err)
+		}
+	}
+	if _, err := ParseOwnerLabelPolicy("last-wins"); err == nil {
+		t.Error("ParseOwnerLabelPolicy(\"last-wins\") error = nil, want an error")
+	}
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// OwnerMapping custom resource, see config/crd/ownermappings.yaml
+const (
+	ownerMappingAPIVersion = "rancher-operator.quiknode.io/v1alpha1"
+	ownerMappingKind       = "OwnerMapping"
+)
+
+// listOwnerMappings reads the OwnerMappings from the manager's cache, which
+// keeps an informer for them once the first List has been served
+func (r *NamespaceReconciler) listOwnerMappings(ctx context.Context) ([]unstructured.Unstructured, error) {
+	mappings := &unstructured.UnstructuredList{}
+	mappings.SetAPIVersion(ownerMappingAPIVersion)
+	mappings.SetKind(ownerMappingKind + "List")
+
+	var reader client.Reader = r.Client
+	if r.Manager != nil {
+		reader = r.Manager.GetCache()
+	}
+	if err := reader.List(ctx, mappings); err != nil {
+		return nil, err
+	}
+	return mappings.Items, nil
+}
+
+// resolveOwnerMapping returns the project of the OwnerMapping matching the
+// namespace's owner. Mappings are authoritative, so they are consulted before
+// any other source. A mapping for the namespace's cluster wins over one that
+// applies to every cluster.
+func (r *NamespaceReconciler) resolveOwnerMapping(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, clusterID string) (string, *ProjectRef, error) {
+	owner, _, err := r.namespaceOwner(ctx, namespaceClient, namespace, clusterID)
+	if isOwnerDisagreement(err) {
+		// Left to the owner label policy applied by the owner lookup
+		return "", nil, nil
+	}
+	if err != nil || owner == "" {
+		return owner, nil, err
+	}
+
+	mappings, err := r.listOwnerMappings(ctx)
+	if err != nil {
+		return owner, nil, clusterError("local", "unable to list owner mappings", err)
+	}
+
+	var match *unstructured.Unstructured
+	for i := range mappings {
+		mapping := &mappings[i]
+		mappingOwner, _, _ := unstructured.NestedString(mapping.Object, "spec", "owner")
+		mappingClusterID, _, _ := unstructured.NestedString(mapping.Object, "spec", "clusterId")
+		if !strings.EqualFold(mappingOwner, owner) {
+			continue
+		}
+		switch mappingClusterID {
+		case clusterID:
+			match = mapping
+		case "":
+			if match == nil {
+				match = mapping
+			}
+		}
+	}
+	if match == nil {
+		return owner, nil, nil
+	}
+
+	projectID, _, _ := unstructured.NestedString(match.Object, "spec", "projectId")
+	if projectID == "" {
+		return owner, nil, nil
+	}
+	projectClusterID, _, _ := unstructured.NestedString(match.Object, "spec", "clusterId")
+	if projectClusterID == "" {
+		if projectClusterID, err = r.projectClusterID(ctx, projectID); err != nil {
+			return owner, nil, err
+		}
+		if projectClusterID == "" {
+			log.FromContext(ctx).Info("owner mapping names an unknown project, ignoring it", "mapping", match.GetName(), "projectId", projectID)
+			return owner, nil, nil
+		}
+	}
+	log.FromContext(ctx).V(1).Info("owner mapping matched", "mapping", match.GetName(), "owner", owner, "projectId", projectID, "projectClusterId", projectClusterID)
+	return owner, &ProjectRef{ProjectID: projectID, ClusterID: projectClusterID, Confidence: ConfidenceExact, Source: SourceOwnerMapping}, nil
+}
+
+// projectClusterID returns the cluster of the project with the given ID, taken
+// from a c-xxx:p-xxx ID or else from the namespace of the Project object. It
+// returns "" when no such project exists.
+func (r *NamespaceReconciler) projectClusterID(ctx context.Context, projectID string) (string, error) {
+	if clusterID := r.extractClusterID(projectID); clusterID != "" {
+		return clusterID, nil
+	}
+	projects, _, err := r.listProjects(ctx, "")
+	if err != nil {
+		return "", clusterError("local", "unable to list projects", err)
+	}
+	for i := range projects {
+		if projects[i].GetName() == projectID {
+			return projects[i].GetNamespace(), nil
+		}
+	}
+	return "", nil
+}
+
+// namespacesForOwnerMapping enqueues the namespaces whose owner, from any
+// configured owner source, matches a created, changed or deleted OwnerMapping
+func (r *NamespaceReconciler) namespacesForOwnerMapping(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	mapping, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	owner, _, _ := unstructured.NestedString(mapping.Object, "spec", "owner")
+	if owner == "" {
+		return nil
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList); err != nil {
+		logger.Error(err, "unable to list namespaces for owner mapping", "mapping", mapping.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range namespaceList.Items {
+		namespace := &namespaceList.Items[i]
+		namespaceOwner, _, err := r.namespaceOwner(ctx, r.Client, namespace, "local")
+		// A namespace whose owner cannot be read is reconciled to find out
+		if err != nil || strings.EqualFold(namespaceOwner, owner) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: namespace.Name}})
+		}
+	}
+	return requests
+}
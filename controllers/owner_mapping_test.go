@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// newOwnerMapping returns an OwnerMapping of owner to the project
+func newOwnerMapping(name, owner, projectID, clusterID string) *unstructured.Unstructured {
+	mapping := &unstructured.Unstructured{}
+	mapping.SetAPIVersion(ownerMappingAPIVersion)
+	mapping.SetKind(ownerMappingKind)
+	mapping.SetName(name)
+	_ = unstructured.SetNestedField(mapping.Object, owner, "spec", "owner")
+	_ = unstructured.SetNestedField(mapping.Object, projectID, "spec", "projectId")
+	if clusterID != "" {
+		_ = unstructured.SetNestedField(mapping.Object, clusterID, "spec", "clusterId")
+	}
+	return mapping
+}
+
+func TestResolveOwnerMappingProjectCluster(t *testing.T) {
+	tests := []struct {
+		name        string
+		mapping     *unstructured.Unstructured
+		wantCluster string
+		wantRef     bool
+	}{
+		{name: "from the Project namespace", mapping: newOwnerMapping("payments", "payments", "p-live", ""), wantCluster: "c-abc", wantRef: true},
+		{name: "from a qualified project ID", mapping: newOwnerMapping("payments", "payments", "c-def:p-live", ""), wantCluster: "c-def", wantRef: true},
+		{name: "unknown project", mapping: newOwnerMapping("payments", "payments", "p-gone", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := newNamespace("payments", map[string]string{appOwnerLabel: "payments"})
+			r := newTestReconciler(newProject("c-abc", "p-live", "payments"), tt.mapping, namespace)
+
+			_, ref, err := r.resolveOwnerMapping(context.Background(), r.Client, namespace, "local")
+			if err != nil {
+				t.Fatalf("resolveOwnerMapping() error = %v", err)
+			}
+			if (ref != nil) != tt.wantRef {
+				t.Fatalf("resolveOwnerMapping() = %+v, want a reference: %v", ref, tt.wantRef)
+			}
+			if ref != nil && ref.ClusterID != tt.wantCluster {
+				t.Errorf("ClusterID = %q, want %q", ref.ClusterID, tt.wantCluster)
+			}
+		})
+	}
+}
+
+func TestNamespacesForOwnerMappingUsesOwnerSources(t *testing.T) {
+	mapping := newOwnerMapping("payments", "payments", "p-live", "c-abc")
+	r := newTestReconciler(
+		newNamespace("by-label", map[string]string{appOwnerLabel: "Payments"}),
+		newNamespace("by-owner-label", map[string]string{"team": "payments"}),
+		newNamespace("other", map[string]string{appOwnerLabel: "orders"}),
+	)
+	r.OwnerLabels = []string{appOwnerLabel, "team"}
+
+	requests := r.namespacesForOwnerMapping(context.Background(), mapping)
+	got := make(map[string]bool)
+	for _, request := range requests {
+		got[request.Name] = true
+	}
+	if len(requests) != 2 || !got["by-label"] || !got["by-owner-label"] {
+		t.Errorf("namespacesForOwnerMapping() = %v, want by-label and by-owner-label", requests)
+	}
+}
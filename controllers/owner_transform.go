@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Supported owner transform types
+const (
+	OwnerTransformTrim         = "trim"
+	OwnerTransformLowercase    = "lowercase"
+	OwnerTransformRegexReplace = "regexReplace"
+	OwnerTransformStripPrefix  = "stripPrefix"
+	// OwnerTransformStripEmailDomain turns "alice@company.com" into "alice"
+	OwnerTransformStripEmailDomain = "stripEmailDomain"
+)
+
+// OwnerTransform is a single step of the pipeline applied to the appOwner
+// value before project resolution
+type OwnerTransform struct {
+	Type        string `json:"type"`
+	Pattern     string `json:"pattern,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	Prefix      string `json:"prefix,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// ParseOwnerTransforms parses a JSON list of transforms, for example
+// [{"type":"trim"},{"type":"stripPrefix","prefix":"team-"}]
+func ParseOwnerTransforms(spec string) ([]OwnerTransform, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var transforms []OwnerTransform
+	if err := json.Unmarshal([]byte(spec), &transforms); err != nil {
+		return nil, fmt.Errorf("unable to parse owner transforms: %w", err)
+	}
+
+	for i := range transforms {
+		t := &transforms[i]
+		switch t.Type {
+		case OwnerTransformTrim, OwnerTransformLowercase, OwnerTransformStripEmailDomain:
+		case OwnerTransformStripPrefix:
+			if t.Prefix == "" {
+				return nil, fmt.Errorf("owner transform %d: stripPrefix requires a prefix", i)
+			}
+		case OwnerTransformRegexReplace:
+			regex, err := regexp.Compile(t.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("owner transform %d: invalid pattern: %w", i, err)
+			}
+			t.regex = regex
+		default:
+			return nil, fmt.Errorf("owner transform %d: unknown type %q", i, t.Type)
+		}
+	}
+
+	return transforms, nil
+}
+
+// applyOwnerTransforms runs the transforms in order and returns the result
+func applyOwnerTransforms(transforms []OwnerTransform, owner string) string {
+	for _, t := range transforms {
+		switch t.Type {
+		case OwnerTransformTrim:
+			owner = strings.TrimSpace(owner)
+		case OwnerTransformLowercase:
+			owner = strings.ToLower(owner)
+		case OwnerTransformStripPrefix:
+			owner = strings.TrimPrefix(owner, t.Prefix)
+		case OwnerTransformStripEmailDomain:
+			if at := strings.LastIndex(owner, "@"); at > 0 {
+				owner = owner[:at]
+			}
+		case OwnerTransformRegexReplace:
+			if t.regex != nil {
+				owner = t.regex.ReplaceAllString(owner, t.Replacement)
+			}
+		}
+	}
+	return owner
+}
+
+// normalizeOwner applies the owner transforms for namespaces using the v2 behavior
+func (r *NamespaceReconciler) normalizeOwner(owner string, behavior Behavior) string {
+	if behavior != BehaviorV2 || len(r.OwnerTransforms) == 0 {
+		return owner
+	}
+	return applyOwnerTransforms(r.OwnerTransforms, owner)
+}
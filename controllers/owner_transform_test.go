@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestOwnerTransformsInSequence(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		owner string
+		want  string
+	}{
+		{
+			name:  "trim, lowercase and strip prefix",
+			spec:  `[{"type":"trim"},{"type":"lowercase"},{"type":"stripPrefix","prefix":"team-"}]`,
+			owner: "  Team-Payments ",
+			want:  "payments",
+		},
+		{
+			// The prefix is matched before lowercasing, so it no longer applies afterwards
+			name:  "order matters",
+			spec:  `[{"type":"stripPrefix","prefix":"team-"},{"type":"lowercase"}]`,
+			owner: "Team-Payments",
+			want:  "team-payments",
+		},
+		{
+			name:  "regex replace after email domain",
+			spec:  `[{"type":"stripEmailDomain"},{"type":"regexReplace","pattern":"[._]+","replacement":"-"}]`,
+			owner: "payments_team.eu@company.com",
+			want:  "payments-team-eu",
+		},
+		{
+			name:  "empty pipeline",
+			spec:  "",
+			owner: "Payments",
+			want:  "Payments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transforms, err := ParseOwnerTransforms(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseOwnerTransforms() error = %v", err)
+			}
+			if got := applyOwnerTransforms(transforms, tt.owner); got != tt.want {
+				t.Errorf("applyOwnerTransforms() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOwnerTransformsRejectsInvalidSteps(t *testing.T) {
+	for _, spec := range []string{
+		`[{"type":"uppercase"}]`,
+		`[{"type":"stripPrefix"}]`,
+		`[{"type":"regexReplace","pattern":"("}]`,
+		`{"type":"trim"}`,
+	} {
+		if _, err := ParseOwnerTransforms(spec); err == nil {
+			t.Errorf("ParseOwnerTransforms(%s) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestNormalizeOwnerOnlyAppliesToV2(t *testing.T) {
+	transforms, err := ParseOwnerTransforms(`[{"type":"lowercase"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &NamespaceReconciler{OwnerTransforms: transforms}
+
+	if got := r.normalizeOwner("Payments", BehaviorV1); got != "Payments" {
+		t.Errorf("normalizeOwner(v1) = %q, want the raw owner", got)
+	}
+	if got := r.normalizeOwner("Payments", BehaviorV2); got != "payments" {
+		t.Errorf("normalizeOwner(v2) = %q, want %q", got, "payments")
+	}
+}
+
+func TestReconcileResolvesEmailOwnerToUsernameProject(t *testing.T) {
+	ctx := context.Background()
+	// Label values cannot hold an email, so the owner comes from an annotation
+	namespace := newNamespace("alice-dev", nil)
+	namespace.Annotations = map[string]string{"example.com/owner": "alice@company.com"}
+	r := newTestReconciler(newProject("local", "p-alice", "alice"), namespace)
+	r.OwnerAnnotation = "example.com/owner"
+	r.DefaultBehavior = BehaviorV2
+	transforms, err := ParseOwnerTransforms(`[{"type":"stripEmailDomain"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.OwnerTransforms = transforms
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "alice-dev"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "alice-dev"}, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if got.Labels[rancherProjectIDLabel] != "p-alice" {
+		t.Errorf("project label = %q, want p-alice", got.Labels[rancherProjectIDLabel])
+	}
+}
+
+func TestStripEmailDomainKeepsNonEmailOwners(t *testing.T) {
+	transforms, err := ParseOwnerTransforms(`[{"type":"stripEmailDomain"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for owner, want := range map[string]string{
+		"alice@company.com":    "alice",
+		"alice@eu@company.com": "alice@eu",
+		"payments":             "payments",
+		"@company.com":         "@company.com",
+	} {
+		if got := applyOwnerTransforms(transforms, owner); got != want {
+			t.Errorf("applyOwnerTransforms(%q) = %q, want %q", owner, got, want)
+		}
+	}
+}
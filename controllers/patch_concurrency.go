@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterPatchSlots holds a semaphore per cluster bounding the namespace
+// patches in flight against it, so small API servers are not overwhelmed
+type clusterPatchSlots struct {
+	mutex sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// acquire waits for a free patch slot on the cluster and returns the function
+// releasing it. It returns the context error when cancelled while waiting.
+func (c *clusterPatchSlots) acquire(ctx context.Context, clusterID string, limit int) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	c.mutex.Lock()
+	if c.slots == nil {
+		c.slots = make(map[string]chan struct{})
+	}
+	slots, ok := c.slots[clusterID]
+	if !ok || cap(slots) != limit {
+		slots = make(chan struct{}, limit)
+		c.slots[clusterID] = slots
+	}
+	c.mutex.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// patchNamespace patches a namespace on the cluster once a patch slot is free
+func (r *NamespaceReconciler) patchNamespace(ctx context.Context, namespaceClient client.Client, clusterID string, namespace *corev1.Namespace, patch client.Patch) error {
+	release, err := r.patchSlots.acquire(ctx, clusterID, r.MaxConcurrentPatches)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return namespaceClient.Patch(ctx, namespace, patch)
+}
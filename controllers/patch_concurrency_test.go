@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestClusterPatchSlotsAcquire(t *testing.T) {
+	ctx := context.Background()
+	var slots clusterPatchSlots
+
+	first, err := slots.acquire(ctx, "c-abc", 2)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if _, err := slots.acquire(ctx, "c-abc", 2); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	// Other clusters have their own slots
+	if _, err := slots.acquire(ctx, "c-other", 2); err != nil {
+		t.Fatalf("acquire() on another cluster error = %v", err)
+	}
+
+	// A full cluster blocks until the context is cancelled
+	cancelled, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := slots.acquire(cancelled, "c-abc", 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire() on a full cluster error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// or a slot is released
+	acquired := make(chan struct{})
+	go func() {
+		if _, err := slots.acquire(ctx, "c-abc", 2); err == nil {
+			close(acquired)
+		}
+	}()
+	first()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquire() still blocked after a slot was released")
+	}
+
+	if _, err := slots.acquire(cancelled, "c-abc", 0); err != nil {
+		t.Errorf("acquire() without a limit error = %v", err)
+	}
+}
+
+func TestReconcileBoundsConcurrentPatches(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	r.MaxConcurrentPatches = 2
+	registerProjectKinds(r.Scheme)
+
+	var mutex sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	objects := []client.Object{newProject("local", "p-payments", "payments")}
+	for i := 0; i < 6; i++ {
+		objects = append(objects, newNamespace(fmt.Sprintf("checkout-%d", i), map[string]string{appOwnerLabel: "payments"}))
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				mutex.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mutex.Unlock()
+
+				time.Sleep(20 * time.Millisecond)
+
+				mutex.Lock()
+				inFlight--
+				mutex.Unlock()
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("checkout-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+				t.Errorf("Reconcile(%s) error = %v", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != r.MaxConcurrentPatches {
+		t.Errorf("at most %d patches in flight, want %d", maxInFlight, r.MaxConcurrentPatches)
+	}
+}
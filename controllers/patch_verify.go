@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// notPersistedRequeueDelay is how long to wait before retrying an assignment
+// whose labels did not survive the patch
+const notPersistedRequeueDelay = 30 * time.Second
+
+// notPersistedError reports that a patch succeeded but the assignment was not
+// found when the namespace was read back, typically because a webhook dropped it
+type notPersistedError struct {
+	missing []string
+}
+
+func (e *notPersistedError) Error() string {
+	return fmt.Sprintf("assignment not persisted, missing %v", e.missing)
+}
+
+// isNotPersisted reports whether err marks an assignment that did not stick
+func isNotPersisted(err error) bool {
+	var notPersisted *notPersistedError
+	return errors.As(err, &notPersisted)
+}
+
+// verifyPatch reads the namespace back and checks that the assignment labels
+// and annotation were kept. Namespaces on the management cluster are read
+// from the API server since the cache may not have observed the patch yet.
+func (r *NamespaceReconciler) verifyPatch(ctx context.Context, namespaceClient client.Client, expected *corev1.Namespace) error {
+	var reader client.Reader = namespaceClient
+	if r.Manager != nil && namespaceClient == r.Client {
+		reader = r.Manager.GetAPIReader()
+	}
+
+	actual := &corev1.Namespace{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: expected.Name}, actual); err != nil {
+		return fmt.Errorf("unable to read back namespace %s: %w", expected.Name, err)
+	}
+
+	var missing []string
+	for _, key := range []string{rancherProjectIDLabel, rancherClusterIDLabel} {
+		if value, ok := expected.Labels[key]; ok && actual.Labels[key] != value {
+			missing = append(missing, "label "+key)
+		}
+	}
+	if value, ok := expected.Annotations[rancherProjectIDAnnotation]; ok && actual.Annotations[rancherProjectIDAnnotation] != value {
+		missing = append(missing, "annotation "+rancherProjectIDAnnotation)
+	}
+	if len(missing) > 0 {
+		return &notPersistedError{missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileRequeuesWhenPatchedLabelIsDropped(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	r.VerifyPatches = true
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	// A mutating webhook silently strips the projectId label from every patch
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newProject("local", "p-live", "payments"), newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if err := c.Patch(ctx, obj, patch, opts...); err != nil {
+					return err
+				}
+				namespace := &corev1.Namespace{}
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), namespace); err != nil {
+					return err
+				}
+				if _, ok := namespace.Labels[rancherProjectIDLabel]; !ok {
+					return nil
+				}
+				delete(namespace.Labels, rancherProjectIDLabel)
+				return c.Update(ctx, namespace)
+			},
+		}).Build()
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}})
+
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != notPersistedRequeueDelay {
+		t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, notPersistedRequeueDelay)
+	}
+	var warnings []string
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.HasPrefix(event, corev1.EventTypeWarning+" AssignmentNotPersisted") {
+			warnings = append(warnings, event)
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], rancherProjectIDLabel) {
+		t.Errorf("AssignmentNotPersisted warnings = %q, want one naming the dropped label", warnings)
+	}
+}
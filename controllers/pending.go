@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// pendingProjectAnnotation records the project a deferred assignment will
+// apply, so observers can see the plan before the Rancher labels change. It
+// is removed when the assignment is applied.
+const pendingProjectAnnotation = "rancher-operator.quiknode.io/pending-project"
+
+// markPending stamps the intended project on a namespace whose assignment was
+// deferred. Failures are logged because the deferral is retried anyway.
+func (r *NamespaceReconciler) markPending(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, projectID string) {
+	if !r.AnnotatePending || namespace.Annotations[pendingProjectAnnotation] == projectID {
+		return
+	}
+
+	patch := client.MergeFrom(namespace.DeepCopy())
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+	namespace.Annotations[pendingProjectAnnotation] = projectID
+	if err := namespaceClient.Patch(ctx, namespace, patch); err != nil {
+		log.FromContext(ctx).Error(err, "unable to annotate pending project", "namespace", namespace.Name, "projectId", projectID)
+	}
+}
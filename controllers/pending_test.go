@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileAnnotatesPendingProjectUntilApplied(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		wantDeferred string
+	}{
+		{name: "enabled", enabled: true, wantDeferred: "p-live"},
+		{name: "disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC))
+			r := newTestReconciler(
+				newProject("local", "p-live", "payments"),
+				newNamespace("payments", map[string]string{appOwnerLabel: "payments"}),
+			)
+			r.Clock = clock
+			r.AnnotatePending = tt.enabled
+			windows, err := ParseMaintenanceWindows("13:00-14:00")
+			if err != nil {
+				t.Fatalf("ParseMaintenanceWindows() error = %v", err)
+			}
+			r.MaintenanceWindows = windows
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+			reconcile := func() *corev1.Namespace {
+				t.Helper()
+				result, err := r.Reconcile(ctx, req)
+				if err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+				clock.Step(result.RequeueAfter)
+				namespace := &corev1.Namespace{}
+				if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+					t.Fatalf("get namespace: %v", err)
+				}
+				return namespace
+			}
+
+			// Deferred until the window opens, the plan is visible on the namespace
+			deferred := reconcile()
+			if got := deferred.Annotations[pendingProjectAnnotation]; got != tt.wantDeferred {
+				t.Errorf("pending project while deferred = %q, want %q", got, tt.wantDeferred)
+			}
+			if got := deferred.Labels[rancherProjectIDLabel]; got != "" {
+				t.Errorf("project label while deferred = %q, want it unset", got)
+			}
+
+			// Applying the assignment clears the annotation
+			applied := reconcile()
+			if got := applied.Labels[rancherProjectIDLabel]; got != "p-live" {
+				t.Errorf("project label = %q, want p-live", got)
+			}
+			if got, ok := applied.Annotations[pendingProjectAnnotation]; ok {
+				t.Errorf("pending project = %q after the assignment was applied, want it removed", got)
+			}
+		})
+	}
+}
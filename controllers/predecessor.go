@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// predecessorAnnotation links a namespace to the namespace it replaces
+	predecessorAnnotation = "rancher-operator.quiknode.io/predecessor"
+)
+
+// carryForwardAnnotations copies the configured annotations from the
+// predecessor namespace onto the namespace. Annotations already present on the
+// namespace are kept, so repeated reconciles do not change anything.
+func (r *NamespaceReconciler) carryForwardAnnotations(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace) error {
+	predecessorName := namespace.Annotations[predecessorAnnotation]
+	if predecessorName == "" || predecessorName == namespace.Name || len(r.CarryForwardAnnotations) == 0 {
+		return nil
+	}
+
+	predecessor := &corev1.Namespace{}
+	if err := namespaceClient.Get(ctx, types.NamespacedName{Name: predecessorName}, predecessor); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	patch := client.MergeFrom(namespace.DeepCopy())
+	copied := 0
+	for _, key := range r.CarryForwardAnnotations {
+		value, ok := predecessor.Annotations[key]
+		if !ok {
+			continue
+		}
+		if _, exists := namespace.Annotations[key]; exists {
+			continue
+		}
+		if namespace.Annotations == nil {
+			namespace.Annotations = make(map[string]string)
+		}
+		namespace.Annotations[key] = value
+		copied++
+	}
+
+	if copied == 0 {
+		return nil
+	}
+
+	if err := namespaceClient.Patch(ctx, namespace, patch); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("copied annotations from predecessor namespace", "namespace", namespace.Name, "predecessor", predecessorName, "count", copied)
+	return nil
+}
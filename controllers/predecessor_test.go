@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileCarriesAnnotationsForwardFromPredecessor(t *testing.T) {
+	ctx := context.Background()
+	previous := `[{"time":"2024-01-05T10:00:00Z","to":"p-live"}]`
+	predecessor := newNamespace("payments-v1", map[string]string{appOwnerLabel: "payments", rancherProjectIDLabel: "p-live"})
+	predecessor.Annotations = map[string]string{
+		assignmentHistoryAnnotation: previous,
+		"example.com/runbook":       "https://runbooks.example.com/payments",
+		"example.com/oncall":        "team-a",
+		"example.com/internal-note": "not carried",
+	}
+	successor := newNamespace("payments-v2", map[string]string{appOwnerLabel: "payments"})
+	successor.Annotations = map[string]string{
+		predecessorAnnotation: "payments-v1",
+		"example.com/oncall":  "team-b",
+	}
+	r := newTestReconciler(newProject("local", "p-live", "payments"), predecessor, successor)
+	r.HistoryLimit = 5
+	r.CarryForwardAnnotations = append([]string{"example.com/runbook", "example.com/oncall"}, DefaultCarryForwardAnnotations...)
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments-v2"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "payments-v2"}, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if got.Annotations["example.com/runbook"] != "https://runbooks.example.com/payments" {
+		t.Errorf("runbook annotation = %q, want it copied from the predecessor", got.Annotations["example.com/runbook"])
+	}
+	// Values already on the new namespace win over the predecessor's
+	if got.Annotations["example.com/oncall"] != "team-b" {
+		t.Errorf("oncall annotation = %q, want the successor's own value", got.Annotations["example.com/oncall"])
+	}
+	if _, ok := got.Annotations["example.com/internal-note"]; ok {
+		t.Error("annotation outside the carry-forward list was copied")
+	}
+
+	// The new assignment extends the carried history instead of starting over
+	var history []assignmentHistoryEntry
+	if err := json.Unmarshal([]byte(got.Annotations[assignmentHistoryAnnotation]), &history); err != nil {
+		t.Fatalf("decode history: %v", err)
+	}
+	if len(history) != 2 || !history[0].Time.Equal(time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)) || history[1].To != "p-live" {
+		t.Errorf("history = %+v, want the predecessor entry followed by the new assignment", history)
+	}
+}
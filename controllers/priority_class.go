@@ -0,0 +1,18 @@
+package controllers
+
+const (
+	// projectPriorityClassAnnotation on a project names the default PriorityClass for its namespaces
+	projectPriorityClassAnnotation = "rancher-operator.quiknode.io/priority-class"
+
+	// DefaultPriorityClassLabel is the default namespace label consumed by scheduling policies
+	DefaultPriorityClassLabel = "rancher-operator.quiknode.io/default-priority-class"
+)
+
+// projectPriorityClass returns the PriorityClass advertised by the resolved
+// project, or an empty string when stamping is disabled or none is set
+func (r *NamespaceReconciler) projectPriorityClass(ref *ProjectRef) string {
+	if r.PriorityClassLabel == "" || ref.Project == nil {
+		return ""
+	}
+	return ref.Project.GetAnnotations()[projectPriorityClassAnnotation]
+}
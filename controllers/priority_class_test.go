@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestProjectPriorityClass(t *testing.T) {
+	project := newProject("local", "p-live", "payments")
+	project.SetAnnotations(map[string]string{projectPriorityClassAnnotation: "high"})
+
+	if got := (&NamespaceReconciler{}).projectPriorityClass(&ProjectRef{Project: project}); got != "" {
+		t.Errorf("projectPriorityClass() = %q without a label configured, want none", got)
+	}
+	r := &NamespaceReconciler{PriorityClassLabel: DefaultPriorityClassLabel}
+	if got := r.projectPriorityClass(&ProjectRef{Project: project}); got != "high" {
+		t.Errorf("projectPriorityClass() = %q, want %q", got, "high")
+	}
+	if got := r.projectPriorityClass(&ProjectRef{ProjectID: "p-live"}); got != "" {
+		t.Errorf("projectPriorityClass() = %q for an external reference, want none", got)
+	}
+}
+
+func TestReconcileFollowsProjectPriorityClass(t *testing.T) {
+	ctx := context.Background()
+	project := newProject("local", "p-live", "payments")
+	project.SetAnnotations(map[string]string{projectPriorityClassAnnotation: "high"})
+	r := newTestReconciler(project, newNamespace("payments", map[string]string{appOwnerLabel: "payments"}))
+	r.PriorityClassLabel = DefaultPriorityClassLabel
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+
+	for _, priorityClass := range []string{"high", "batch"} {
+		project.SetAnnotations(map[string]string{projectPriorityClassAnnotation: priorityClass})
+		if err := r.Update(ctx, project); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+			t.Fatal(err)
+		}
+		// An assigned namespace picks up a changed priority class as well
+		if got := namespace.Labels[DefaultPriorityClassLabel]; got != priorityClass {
+			t.Errorf("priority class label = %q, want %q", got, priorityClass)
+		}
+	}
+}
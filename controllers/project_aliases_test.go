@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"testing"
+)
+
+func TestProjectMatchesAliases(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		aliases    string
+		owner      string
+		want       bool
+	}{
+		{name: "comma separated alias", annotation: DefaultAliasesAnnotation, aliases: "billing, payments-legacy", owner: "payments-legacy", want: true},
+		{name: "JSON list alias", annotation: DefaultAliasesAnnotation, aliases: `["billing","payments-legacy"]`, owner: "billing", want: true},
+		{name: "owner matches no alias", annotation: DefaultAliasesAnnotation, aliases: "billing, payments-legacy", owner: "orders", want: false},
+		{name: "alias matching disabled", annotation: "", aliases: "billing, payments-legacy", owner: "billing", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler()
+			r.AliasesAnnotation = tt.annotation
+			project := newProject("local", "p-live", "payments")
+			project.SetAnnotations(map[string]string{DefaultAliasesAnnotation: tt.aliases})
+
+			if got := r.projectMatches(project, tt.owner); got != tt.want {
+				t.Errorf("projectMatches(%q) = %v, want %v", tt.owner, got, tt.want)
+			}
+		})
+	}
+}
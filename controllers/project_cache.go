@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// projectCache holds the last full list of Rancher Projects. Cached objects
+// are shared between reconciles and must not be modified.
+type projectCache struct {
+	mutex    sync.RWMutex
+	projects []unstructured.Unstructured
+	loaded   time.Time
+}
+
+// get returns the cached projects if they are younger than ttl
+func (c *projectCache) get(ttl time.Duration, now time.Time) ([]unstructured.Unstructured, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.loaded.IsZero() || now.Sub(c.loaded) > ttl {
+		return nil, false
+	}
+	return c.projects, true
+}
+
+func (c *projectCache) set(projects []unstructured.Unstructured, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.projects = projects
+	c.loaded = now
+}
+
+// invalidate drops the cached projects so the next reconcile lists them again
+func (c *projectCache) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.projects = nil
+	c.loaded = time.Time{}
+}
+
+// loadProjects lists Rancher Projects, optionally narrowed by the list options
+func loadProjects(ctx context.Context, reader client.Reader, opts ...client.ListOption) ([]unstructured.Unstructured, error) {
+	projectList, err := loadProjectList(ctx, reader, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return projectList.Items, nil
+}
+
+func loadProjectList(ctx context.Context, reader client.Reader, opts ...client.ListOption) (*unstructured.UnstructuredList, error) {
+	projectList := &unstructured.UnstructuredList{}
+	projectList.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "management.cattle.io",
+		Version: "v3",
+		Kind:    "ProjectList",
+	})
+
+	if err := reader.List(ctx, projectList, opts...); err != nil {
+		return nil, fmt.Errorf("unable to list projects: %w", err)
+	}
+	return projectList, nil
+}
+
+// projectListPageSize is the page size used when the project List is capped
+const projectListPageSize = 500
+
+// loadProjectsCapped lists Rancher Projects page by page and stops once max
+// projects have been loaded. It reports whether more projects were left unread.
+func loadProjectsCapped(ctx context.Context, reader client.Reader, max int, opts ...client.ListOption) ([]unstructured.Unstructured, bool, error) {
+	var projects []unstructured.Unstructured
+	continueToken := ""
+	for {
+		limit := projectListPageSize
+		if remaining := max - len(projects); remaining < limit {
+			limit = remaining
+		}
+
+		pageOpts := append([]client.ListOption{client.Limit(int64(limit)), client.Continue(continueToken)}, opts...)
+		page, err := loadProjectList(ctx, reader, pageOpts...)
+		if err != nil {
+			return nil, false, err
+		}
+		projects = append(projects, page.Items...)
+		continueToken = page.GetContinue()
+
+		if continueToken == "" {
+			return projects, false, nil
+		}
+		if len(projects) >= max {
+			return projects, true, nil
+		}
+	}
+}
+
+// timedLoadProjects loads at most max projects, or all of them when max is
+// zero, and records the List latency
+func (r *NamespaceReconciler) timedLoadProjects(ctx context.Context, max int, opts ...client.ListOption) ([]unstructured.Unstructured, bool, error) {
+	start := r.clock().Now()
+	defer func() { r.listLatency.observe(r.clock().Since(start), r.clock().Now()) }()
+
+	if max > 0 {
+		return loadProjectsCapped(ctx, r.Client, max, opts...)
+	}
+	projects, err := loadProjects(ctx, r.Client, opts...)
+	return projects, false, err
+}
+
+// listProjects returns the projects visible to a namespace on the given
+// cluster, served from the project cache when it is enabled. Without the
+// cache at most MaxProjectList projects are loaded and truncation is reported.
+func (r *NamespaceReconciler) listProjects(ctx context.Context, clusterID string) ([]unstructured.Unstructured, bool, error) {
+	// Namespaces on local may reference a project in any cluster, so the list
+	// is only narrowed for downstream clusters or when pinned to a single cluster
+	namespaced := clusterID != "" && (clusterID != "local" || r.SingleCluster != "")
+
+	if r.ProjectLister != nil {
+		listClusterID := ""
+		if namespaced {
+			listClusterID = clusterID
+		}
+		start := r.clock().Now()
+		projects, err := r.ProjectLister.ListProjects(ctx, listClusterID)
+		r.listLatency.observe(r.clock().Since(start), r.clock().Now())
+		return projects, false, err
+	}
+
+	if r.ProjectCacheTTL <= 0 {
+		var listOptions []client.ListOption
+		if namespaced {
+			// Filter by cluster namespace if specified
+			listOptions = append(listOptions, client.InNamespace(clusterID))
+		}
+		return r.timedLoadProjects(ctx, r.MaxProjectList, listOptions...)
+	}
+
+	projects, ok := r.projects.get(r.ProjectCacheTTL, r.clock().Now())
+	if !ok {
+		var err error
+		if projects, _, err = r.timedLoadProjects(ctx, 0); err != nil {
+			return nil, false, err
+		}
+		r.projects.set(projects, r.clock().Now())
+	}
+
+	if !namespaced {
+		return projects, false, nil
+	}
+
+	var filtered []unstructured.Unstructured
+	for i := range projects {
+		if projects[i].GetNamespace() == clusterID {
+			filtered = append(filtered, projects[i])
+		}
+	}
+	return filtered, false, nil
+}
+
+// warmProjectCache populates the project cache so the first reconciles after
+// startup do not each issue a List
+func (r *NamespaceReconciler) warmProjectCache(ctx context.Context, reader client.Reader) {
+	logger := log.FromContext(ctx)
+
+	projects, err := loadProjects(ctx, reader)
+	if err != nil {
+		logger.Error(err, "unable to warm project cache")
+		return
+	}
+
+	r.projects.set(projects, r.clock().Now())
+	logger.Info("project cache warmed", "projectCount", len(projects))
+}
+
+// rebuildProjectCache rebuilds the project cache every interval until the
+// context is cancelled, independent of the cache TTL, so missed changes do not
+// linger
+func (r *NamespaceReconciler) rebuildProjectCache(ctx context.Context, reader client.Reader, interval time.Duration) error {
+	ticker := r.clock().NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			r.warmProjectCache(ctx, reader)
+		}
+	}
+}
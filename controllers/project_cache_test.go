@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestWarmedProjectCacheServesFirstReconcile(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(newProject("local", "p-live", "payments"))
+	r.ProjectCacheTTL = time.Hour
+
+	// Warm up from the API reader before the controller starts
+	r.warmProjectCache(ctx, r.Client)
+	if projects, ok := r.projects.get(r.ProjectCacheTTL, r.clock().Now()); !ok || len(projects) != 1 {
+		t.Fatalf("project cache = %d projects (ok=%v) after warmup, want 1", len(projects), ok)
+	}
+
+	projectLists := 0
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if u, ok := list.(*unstructured.UnstructuredList); ok && u.GetKind() == "ProjectList" {
+					projectLists++
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if projectLists != 0 {
+		t.Errorf("first reconcile listed projects %d times, want a cache hit", projectLists)
+	}
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "payments"}, namespace); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if namespace.Labels[rancherProjectIDLabel] != "p-live" {
+		t.Errorf("project label = %q, want p-live from the warmed cache", namespace.Labels[rancherProjectIDLabel])
+	}
+}
+
+func TestProjectCacheRebuildsOnSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := clocktesting.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := newTestReconciler(newProject("local", "p-live", "payments"))
+	r.Clock = clock
+	r.warmProjectCache(ctx, r.Client)
+
+	// A project created while its watch event was missed
+	if err := r.Create(ctx, newProject("local", "p-orders", "orders")); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	cached := func() int {
+		// The TTL is long enough that only a rebuild can refresh the cache
+		projects, _ := r.projects.get(24*time.Hour, clock.Now())
+		return len(projects)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = r.rebuildProjectCache(ctx, r.Client, 10*time.Minute)
+	}()
+	for !clock.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Step(9 * time.Minute)
+	if got := cached(); got != 1 {
+		t.Fatalf("cache holds %d projects before the rebuild interval, want 1", got)
+	}
+
+	clock.Step(time.Minute)
+	deadline := time.Now().Add(5 * time.Second)
+	for cached() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("cache holds %d projects after the rebuild interval, want 2", cached())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
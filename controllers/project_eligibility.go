@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+)
+
+// projectIneligibleError reports that projects match the owner but none of
+// them is eligible. Unlike a missing project this may change, for example
+// when the required label is added, so the namespace is retried.
+type projectIneligibleError struct {
+	projectName string
+	matches     int
+}
+
+func (e *projectIneligibleError) Error() string {
+	return fmt.Sprintf("%d projects match name %q but none is eligible", e.matches, e.projectName)
+}
+
+func isProjectIneligible(err error) bool {
+	var ineligible *projectIneligibleError
+	return errors.As(err, &ineligible)
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileRequeuesIneligibleProject(t *testing.T) {
+	ready := newProject("local", "p-payments", "payments")
+	ready.SetLabels(map[string]string{"example.io/ready": "true"})
+	tests := []struct {
+		name        string
+		project     client.Object
+		requeue     bool
+		wantRequeue bool
+		wantEvent   bool
+		wantProject string
+	}{
+		{name: "ineligible project is retried", project: newProject("local", "p-payments", "payments"), requeue: true, wantRequeue: true, wantEvent: true},
+		{name: "retry disabled", project: newProject("local", "p-payments", "payments"), wantEvent: true},
+		{name: "missing project is not retried", project: newProject("local", "p-billing", "billing"), requeue: true},
+		{name: "eligible project is assigned", project: ready, requeue: true, wantProject: "p-payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(tt.project, newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}))
+			r.ProjectRequiredLabel = labels.SelectorFromSet(labels.Set{"example.io/ready": "true"})
+			r.RequeueIneligible = tt.requeue
+			recorder := record.NewFakeRecorder(10)
+			r.Recorder = recorder
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if result.Requeue != tt.wantRequeue {
+				t.Errorf("Requeue = %v, want %v", result.Requeue, tt.wantRequeue)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != tt.wantProject {
+				t.Errorf("project label = %q, want %q", got.Labels[rancherProjectIDLabel], tt.wantProject)
+			}
+			var ineligibleEvents int
+			for len(recorder.Events) > 0 {
+				if strings.HasPrefix(<-recorder.Events, corev1.EventTypeNormal+" "+string(ReasonProjectIneligible)+" 1 projects match name \"payments\"") {
+					ineligibleEvents++
+				}
+			}
+			if (ineligibleEvents == 1) != tt.wantEvent || ineligibleEvents > 1 {
+				t.Errorf("ProjectIneligible events = %d, want event %v", ineligibleEvents, tt.wantEvent)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// pagedProjects serves the Project List in pages honoring Limit and Continue,
+// which the fake client ignores, and counts the projects it handed out
+func pagedProjects(r *NamespaceReconciler, objects []client.Object, served *int) client.Client {
+	return fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if err := c.List(ctx, list, opts...); err != nil {
+					return err
+				}
+				projects, ok := list.(*unstructured.UnstructuredList)
+				if !ok || projects.GetKind() != "ProjectList" {
+					return nil
+				}
+				listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+				start, _ := strconv.Atoi(listOpts.Continue)
+				end := len(projects.Items)
+				if listOpts.Limit > 0 && start+int(listOpts.Limit) < end {
+					end = start + int(listOpts.Limit)
+					projects.SetContinue(strconv.Itoa(end))
+				}
+				projects.Items = projects.Items[start:end]
+				*served += len(projects.Items)
+				return nil
+			},
+		}).Build()
+}
+
+func TestFindProjectByNameEnforcesProjectListCap(t *testing.T) {
+	var objects []client.Object
+	for i := 0; i < 30; i++ {
+		objects = append(objects, newProject("local", fmt.Sprintf("p-%02d", i), fmt.Sprintf("team-%02d", i)))
+	}
+
+	tests := []struct {
+		name        string
+		owner       string
+		wantProject string
+		wantErr     string
+	}{
+		{name: "found within the cap", owner: "team-03", wantProject: "p-03"},
+		{name: "beyond the cap", owner: "team-25", wantErr: "not found within the first 10 projects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler()
+			r.MaxProjectList = 10
+			served := 0
+			r.Client = pagedProjects(r, objects, &served)
+
+			project, err := r.findProjectByName(context.Background(), tt.owner, "local")
+
+			if served > r.MaxProjectList {
+				t.Errorf("loaded %d projects, want at most %d", served, r.MaxProjectList)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "--project-cache-ttl") {
+					t.Errorf("findProjectByName() error = %v, want %q with a cache hint", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findProjectByName() error = %v", err)
+			}
+			if project == nil || project.GetName() != tt.wantProject {
+				t.Errorf("findProjectByName() = %v, want %s", project, tt.wantProject)
+			}
+		})
+	}
+}
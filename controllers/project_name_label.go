@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// projectNameLabel carries the resolved project's display name for
+// dashboards that key off a human readable name
+const projectNameLabel = "rancher-operator.quiknode.io/project-name"
+
+// projectNameLabelValue returns the project's display name sanitized to a
+// label value, or an empty string when the project is unknown
+func (r *NamespaceReconciler) projectNameLabelValue(ref *ProjectRef) string {
+	if !r.LabelProjectName || ref.Project == nil {
+		return ""
+	}
+	displayName, _, _ := unstructured.NestedString(ref.Project.Object, "spec", "displayName")
+	return sanitizeLabelValue(displayName)
+}
+
+// sanitizeLabelValue replaces characters not allowed in label values with
+// '-', trims the value to 63 characters and makes it start and end with an
+// alphanumeric character
+func sanitizeLabelValue(value string) string {
+	value = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, value)
+
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	return strings.TrimFunc(value, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "payments", want: "payments"},
+		{value: "Payments Team", want: "Payments-Team"},
+		{value: "(Payments) & Billing!", want: "Payments----Billing"},
+		{value: "payments.eu_west-1", want: "payments.eu_west-1"},
+		{value: "Zahlungsverkehr Übersee", want: "Zahlungsverkehr--bersee"},
+		{value: strings.Repeat("a", 62) + " b", want: strings.Repeat("a", 62)},
+		{value: "***", want: ""},
+	}
+	for _, tt := range tests {
+		got := sanitizeLabelValue(tt.value)
+		if got != tt.want {
+			t.Errorf("sanitizeLabelValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if errs := validation.IsValidLabelValue(got); len(errs) > 0 {
+			t.Errorf("sanitizeLabelValue(%q) = %q is not a valid label value: %v", tt.value, got, errs)
+		}
+	}
+}
+
+func TestReconcileLabelsProjectName(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		want    string
+	}{
+		{name: "enabled", enabled: true, want: "Payments-Team"},
+		{name: "disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			// Label values cannot hold the display name, so the owner comes from an annotation
+			namespace := newNamespace("checkout", nil)
+			namespace.Annotations = map[string]string{"example.io/owner": "Payments Team"}
+			r := newTestReconciler(newProject("local", "p-payments", "Payments Team"), namespace)
+			r.OwnerAnnotation = "example.io/owner"
+			r.LabelProjectName = tt.enabled
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != "p-payments" {
+				t.Fatalf("project label = %q, want p-payments", got.Labels[rancherProjectIDLabel])
+			}
+			if got.Labels[projectNameLabel] != tt.want {
+				t.Errorf("%s = %q, want %q", projectNameLabel, got.Labels[projectNameLabel], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// projectNameTemplateData is the data available to the project name template
+type projectNameTemplateData struct {
+	// Owner is the normalized owner value
+	Owner string
+	// Namespace is the namespace name
+	Namespace string
+	// ClusterID is the cluster the namespace lives on
+	ClusterID string
+}
+
+// ParseProjectNameTemplate parses the template deriving the project name
+// from the owner, for example "{{ .Owner }}-apps"
+func ParseProjectNameTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("project-name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse project name template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// projectNameFor returns the project name looked up for owner, rendered
+// through the project name template when one is set
+func (r *NamespaceReconciler) projectNameFor(owner string, namespace *corev1.Namespace, clusterID string) (string, error) {
+	if r.ProjectNameTemplate == nil {
+		return owner, nil
+	}
+
+	var out bytes.Buffer
+	data := projectNameTemplateData{Owner: owner, Namespace: namespace.Name, ClusterID: clusterID}
+	if err := r.ProjectNameTemplate.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("unable to render project name template: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
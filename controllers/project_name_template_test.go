@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileLooksUpTemplatedProjectName(t *testing.T) {
+	ctx := context.Background()
+	// A project named after the bare owner exists too and must not be chosen
+	r := newTestReconciler(
+		newProject("local", "p-payments", "payments"),
+		newProject("local", "p-payments-apps", "payments-apps"),
+		newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+	)
+	tmpl, err := ParseProjectNameTemplate("{{ .Owner }}-apps")
+	if err != nil {
+		t.Fatalf("ParseProjectNameTemplate() error = %v", err)
+	}
+	r.ProjectNameTemplate = tmpl
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if got.Labels[rancherProjectIDLabel] != "p-payments-apps" {
+		t.Errorf("project label = %q, want p-payments-apps", got.Labels[rancherProjectIDLabel])
+	}
+}
+
+func TestProjectNameTemplateData(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{template: "", want: "payments"},
+		{template: "{{ .Owner }}-{{ .ClusterID }}", want: "payments-c-abc"},
+		{template: "{{ .Namespace }}", want: "checkout"},
+		{template: "{{ .Team }}", wantErr: true},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseProjectNameTemplate(tt.template)
+		if err != nil {
+			t.Fatalf("ParseProjectNameTemplate(%q) error = %v", tt.template, err)
+		}
+		r := &NamespaceReconciler{ProjectNameTemplate: tmpl}
+		got, err := r.projectNameFor("payments", newNamespace("checkout", nil), "c-abc")
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("projectNameFor(%q) = (%q, %v), want (%q, error %v)", tt.template, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"context"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// resolveProjectPool picks a project for the namespace from the configured
+// pool by hashing the namespace name, spreading synthetic fleets evenly. The
+// same name always maps to the same pool entry while the pool is unchanged.
+// It returns the chosen project name and a nil reference when that project
+// does not exist.
+func (r *NamespaceReconciler) resolveProjectPool(ctx context.Context, namespace *corev1.Namespace, clusterID string) (string, *ProjectRef, error) {
+	if len(r.ProjectPool) == 0 {
+		return "", nil, nil
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(namespace.Name))
+	projectName := r.ProjectPool[hash.Sum64()%uint64(len(r.ProjectPool))]
+
+	project, err := r.findProjectByName(withNamespace(ctx, namespace), projectName, clusterID)
+	if err != nil || project == nil {
+		return projectName, nil, err
+	}
+	ref := r.projectRef(project, projectName)
+	ref.Source = SourcePool
+	return projectName, ref, nil
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileSpreadsNamespacesAcrossProjectPool(t *testing.T) {
+	ctx := context.Background()
+	objects := []client.Object{
+		newProject("local", "p-alpha", "alpha"),
+		newProject("local", "p-beta", "beta"),
+		newProject("local", "p-gamma", "gamma"),
+	}
+	var names []string
+	for i := 0; i < 30; i++ {
+		name := fmt.Sprintf("load-test-%d", i)
+		names = append(names, name)
+		// The owner label is ignored while a pool is configured
+		objects = append(objects, newNamespace(name, map[string]string{appOwnerLabel: "payments"}))
+	}
+	objects = append(objects, newProject("local", "p-payments", "payments"))
+	r := newTestReconciler(objects...)
+	r.ProjectPool = []string{"alpha", "beta", "gamma"}
+
+	assigned := make(map[string]string)
+	perProject := make(map[string]int)
+	for _, name := range names {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			t.Fatalf("get namespace: %v", err)
+		}
+		assigned[name] = namespace.Labels[rancherProjectIDLabel]
+		perProject[assigned[name]]++
+	}
+
+	for _, projectID := range []string{"p-alpha", "p-beta", "p-gamma"} {
+		if perProject[projectID] == 0 {
+			t.Errorf("no namespace assigned to %s, distribution %v", projectID, perProject)
+		}
+	}
+	if total := perProject["p-alpha"] + perProject["p-beta"] + perProject["p-gamma"]; total != len(names) {
+		t.Errorf("%d of %d namespaces assigned to the pool, distribution %v", total, len(names), perProject)
+	}
+
+	// The choice depends only on the namespace name and the pool
+	for _, name := range names[:5] {
+		projectName, ref, err := r.resolveProjectPool(ctx, newNamespace(name, nil), "local")
+		if err != nil || ref == nil {
+			t.Fatalf("resolveProjectPool(%s) = (%q, %v, %v)", name, projectName, ref, err)
+		}
+		if ref.ProjectID != assigned[name] || ref.Source != SourcePool {
+			t.Errorf("resolveProjectPool(%s) = %s from %s, want %s from %s", name, ref.ProjectID, ref.Source, assigned[name], SourcePool)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// Labels identifying the namespace an operator-managed binding was created for
+	bindingNamespaceLabel = "rancher-operator.quiknode.io/namespace"
+	bindingClusterLabel   = "rancher-operator.quiknode.io/cluster"
+
+	// DefaultRBACRoleTemplate is the role template granted to the owner group
+	DefaultRBACRoleTemplate = "project-member"
+)
+
+var projectRoleTemplateBindingGVK = schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ProjectRoleTemplateBinding"}
+
+// projectBindingName is the name of the binding managed for a namespace.
+// Bindings live in the project's namespace, so the name only has to be unique
+// among the namespaces assigned to one project.
+func projectBindingName(clusterID, namespace string) string {
+	return "qn-" + clusterID + "-" + namespace
+}
+
+// syncProjectRoleBinding grants the owner group the configured role template
+// in the namespace's project. Bindings the operator created for the namespace
+// in any other project, left behind by a reassignment, are deleted.
+func (r *NamespaceReconciler) syncProjectRoleBinding(ctx context.Context, namespace *corev1.Namespace, owner string, ref *ProjectRef, clusterID, projectClusterID string) error {
+	logger := log.FromContext(ctx)
+	projectName := ref.ProjectID[strings.LastIndex(ref.ProjectID, ":")+1:]
+
+	bindings := &unstructured.UnstructuredList{}
+	bindings.SetGroupVersionKind(projectRoleTemplateBindingGVK.GroupVersion().WithKind("ProjectRoleTemplateBindingList"))
+	if err := r.List(ctx, bindings, client.MatchingLabels{bindingNamespaceLabel: namespace.Name, bindingClusterLabel: clusterID}); err != nil {
+		return fmt.Errorf("unable to list project role bindings: %w", err)
+	}
+
+	current := false
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if binding.GetNamespace() == projectName {
+			current = true
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Delete(ctx, binding)); err != nil {
+			return fmt.Errorf("unable to delete project role binding %s/%s: %w", binding.GetNamespace(), binding.GetName(), err)
+		}
+		logger.Info("deleted project role binding of previous project", "namespace", namespace.Name, "binding", binding.GetName(), "previousProjectId", binding.GetNamespace())
+	}
+	if current {
+		return nil
+	}
+
+	roleTemplate := r.RBACRoleTemplate
+	if roleTemplate == "" {
+		roleTemplate = DefaultRBACRoleTemplate
+	}
+	binding := &unstructured.Unstructured{}
+	binding.SetGroupVersionKind(projectRoleTemplateBindingGVK)
+	binding.SetNamespace(projectName)
+	binding.SetName(projectBindingName(clusterID, namespace.Name))
+	binding.SetLabels(map[string]string{bindingNamespaceLabel: namespace.Name, bindingClusterLabel: clusterID})
+	binding.Object["projectName"] = projectClusterID + ":" + projectName
+	binding.Object["roleTemplateName"] = roleTemplate
+	binding.Object["groupPrincipalName"] = r.RBACGroupPrincipalPrefix + owner
+	if err := client.IgnoreAlreadyExists(r.Create(ctx, binding)); err != nil {
+		return fmt.Errorf("unable to create project role binding: %w", err)
+	}
+	logger.Info("created project role binding", "namespace", namespace.Name, "binding", binding.GetName(), "projectId", projectName, "roleTemplate", roleTemplate)
+	return nil
+}
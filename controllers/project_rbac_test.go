@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReassignmentMovesProjectRoleBinding(t *testing.T) {
+	ctx := context.Background()
+
+	// The namespace was assigned to p-old before its owner changed to billing
+	oldBinding := &unstructured.Unstructured{}
+	oldBinding.SetGroupVersionKind(projectRoleTemplateBindingGVK)
+	oldBinding.SetNamespace("p-old")
+	oldBinding.SetName(projectBindingName("local", "payments"))
+	oldBinding.SetLabels(map[string]string{bindingNamespaceLabel: "payments", bindingClusterLabel: "local"})
+	// Bindings of other namespaces and unmanaged bindings are left alone
+	otherBinding := oldBinding.DeepCopy()
+	otherBinding.SetName(projectBindingName("local", "orders"))
+	otherBinding.SetLabels(map[string]string{bindingNamespaceLabel: "orders", bindingClusterLabel: "local"})
+
+	r := newTestReconciler(
+		newProject("local", "p-old", "payments"),
+		newProject("local", "p-new", "billing"),
+		newNamespace("payments", map[string]string{appOwnerLabel: "billing", rancherProjectIDLabel: "p-old"}),
+		oldBinding, otherBinding,
+	)
+	r.ManageProjectRBAC = true
+	r.RBACGroupPrincipalPrefix = "keycloakoidc_group://"
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	bindings := &unstructured.UnstructuredList{}
+	bindings.SetGroupVersionKind(projectRoleTemplateBindingGVK.GroupVersion().WithKind("ProjectRoleTemplateBindingList"))
+	if err := r.List(ctx, bindings); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]*unstructured.Unstructured)
+	for i := range bindings.Items {
+		got[bindings.Items[i].GetNamespace()+"/"+bindings.Items[i].GetName()] = &bindings.Items[i]
+	}
+	if _, ok := got["p-old/"+projectBindingName("local", "payments")]; ok {
+		t.Error("binding of the previous project was not deleted")
+	}
+	if _, ok := got["p-old/"+projectBindingName("local", "orders")]; !ok {
+		t.Error("binding of another namespace was deleted")
+	}
+	binding, ok := got["p-new/"+projectBindingName("local", "payments")]
+	if !ok {
+		t.Fatalf("no binding created in the new project, bindings = %v", got)
+	}
+	if binding.Object["projectName"] != "local:p-new" || binding.Object["roleTemplateName"] != DefaultRBACRoleTemplate ||
+		binding.Object["groupPrincipalName"] != "keycloakoidc_group://billing" {
+		t.Errorf("binding = %v, want the owner group bound to local:p-new as %s", binding.Object, DefaultRBACRoleTemplate)
+	}
+}
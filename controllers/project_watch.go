@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// projectEligible reports whether a project carries the required eligibility labels
+func (r *NamespaceReconciler) projectEligible(project *unstructured.Unstructured) bool {
+	return r.ProjectRequiredLabel == nil || r.ProjectRequiredLabel.Matches(labels.Set(project.GetLabels()))
+}
+
+// projectBecameEligible only passes Project updates where the required label
+// was just added, which is when pending namespaces may now resolve
+func (r *NamespaceReconciler) projectBecameEligible() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldProject, ok := e.ObjectOld.(*unstructured.Unstructured)
+			if !ok {
+				return false
+			}
+			newProject, ok := e.ObjectNew.(*unstructured.Unstructured)
+			if !ok {
+				return false
+			}
+			return !r.projectEligible(oldProject) && r.projectEligible(newProject)
+		},
+	}
+}
+
+// namespacesForProject enqueues the unassigned namespaces that resolve to a
+// project that has become eligible. Namespaces go through the same resolution
+// chain as Reconcile, so owner labels, annotations, ConfigMaps, name templates
+// and profiles all count, not just the appOwner label.
+func (r *NamespaceReconciler) namespacesForProject(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	project, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	// The cached copy of the project still lacks the required label
+	r.projects.invalidate()
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList); err != nil {
+		logger.Error(err, "unable to list namespaces for eligible project", "projectId", project.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range namespaceList.Items {
+		namespace := &namespaceList.Items[i]
+		if namespace.Labels[rancherProjectIDLabel] != "" {
+			continue
+		}
+		res, err := r.resolveNamespace(ctx, r.Client, namespace, "local")
+		if err != nil {
+			logger.V(1).Info("unable to resolve namespace for eligible project", "namespace", namespace.Name, "projectId", project.GetName(), "error", err)
+			continue
+		}
+		if !resolvesToProject(res, project) && (res.Owner == "" || !r.projectMatches(project, res.Owner)) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: namespace.Name}})
+	}
+
+	if len(requests) > 0 {
+		logger.Info("project gained required label, requeueing namespaces", "projectId", project.GetName(), "namespaces", len(requests))
+	}
+	return requests
+}
+
+// resolvesToProject reports whether the resolution picked the given project
+func resolvesToProject(res namespaceResolution, project *unstructured.Unstructured) bool {
+	if res.Ref == nil {
+		return false
+	}
+	projectID := res.Ref.ProjectID[strings.LastIndex(res.Ref.ProjectID, ":")+1:]
+	return projectID == project.GetName() && (res.Ref.ClusterID == "" || res.Ref.ClusterID == project.GetNamespace())
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestNamespacesForProjectUsesOwnerResolution(t *testing.T) {
+	ctx := context.Background()
+
+	annotated := newNamespace("billing", nil)
+	annotated.Annotations = map[string]string{"example.com/team": "billing"}
+	r := newTestReconciler(
+		newNamespace("billing-label", map[string]string{"team": "billing"}),
+		annotated,
+		newNamespace("billing-assigned", map[string]string{"team": "billing", rancherProjectIDLabel: "p-other"}),
+		newNamespace("orders", map[string]string{"team": "orders"}),
+	)
+	r.OwnerLabels = []string{"team"}
+	r.OwnerAnnotation = "example.com/team"
+	template, err := ParseProjectNameTemplate("{{ .Owner }}-apps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ProjectNameTemplate = template
+	selector, err := labels.Parse("eligible=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ProjectRequiredLabel = selector
+	r.ProjectCacheTTL = time.Hour
+
+	// The cache was filled while the project still lacked the required label
+	project := newProject("local", "p-billing", "billing-apps")
+	r.projects.set([]unstructured.Unstructured{*project.DeepCopy()}, r.clock().Now())
+
+	project.SetLabels(map[string]string{"eligible": "true"})
+	if err := r.Create(ctx, project); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, request := range r.namespacesForProject(ctx, project) {
+		got = append(got, request.Name)
+	}
+	sort.Strings(got)
+	if want := []string{"billing", "billing-label"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("namespacesForProject() = %v, want %v", got, want)
+	}
+
+	cached, ok := r.projects.get(r.ProjectCacheTTL, r.clock().Now())
+	if !ok || len(cached) != 1 || !r.projectEligible(&cached[0]) {
+		t.Errorf("project cache = %v, want it refreshed with the eligible project", cached)
+	}
+}
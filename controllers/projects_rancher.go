@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/clock"
+)
+
+// ProjectLister lists Rancher Projects from somewhere other than the Project
+// objects on the management cluster. Projects are returned in the shape of
+// those objects, named by project ID in the namespace of their cluster, so
+// they are matched and selected exactly like Project objects.
+type ProjectLister interface {
+	// ListProjects returns the projects of the cluster, or of every cluster
+	// when clusterID is empty
+	ListProjects(ctx context.Context, clusterID string) ([]unstructured.Unstructured, error)
+}
+
+// RancherAPIProjectLister lists projects through Rancher's public v3 REST
+// API, bypassing the Kubernetes API of the management cluster. It sends
+// GET <url>/v3/projects with a bearer token, narrowed to a cluster with
+// clusterId=<clusterId>, and follows the collection's pagination.
+type RancherAPIProjectLister struct {
+	URL      string
+	Token    string
+	CacheTTL time.Duration
+	Client   *http.Client
+	// Clock times cache expiry and defaults to the real clock
+	Clock clock.WithTicker
+
+	cacheMutex sync.Mutex
+	cache      map[string]rancherAPICacheEntry
+}
+
+type rancherAPICacheEntry struct {
+	projects []unstructured.Unstructured
+	expires  time.Time
+}
+
+type rancherProjectCollection struct {
+	Data       []rancherAPIProject `json:"data"`
+	Pagination struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+type rancherAPIProject struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	ClusterID   string            `json:"clusterId"`
+	State       string            `json:"state"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Created     string            `json:"created"`
+}
+
+// NewRancherAPIProjectLister creates a lister for the Rancher server at
+// rancherURL authenticating with token, with default timeout and cache TTL
+func NewRancherAPIProjectLister(rancherURL, token string) *RancherAPIProjectLister {
+	return &RancherAPIProjectLister{
+		URL:      strings.TrimSuffix(rancherURL, "/"),
+		Token:    token,
+		CacheTTL: defaultHTTPResolverCacheTTL,
+		Client:   &http.Client{Timeout: defaultHTTPResolverTimeout},
+	}
+}
+
+// ListProjects implements ProjectLister. Projects being removed are left out.
+// Callers must not modify the returned projects.
+func (l *RancherAPIProjectLister) ListProjects(ctx context.Context, clusterID string) ([]unstructured.Unstructured, error) {
+	now := clockOrReal(l.Clock).Now()
+
+	l.cacheMutex.Lock()
+	entry, cached := l.cache[clusterID]
+	l.cacheMutex.Unlock()
+	if cached && now.Before(entry.expires) {
+		return entry.projects, nil
+	}
+
+	projects, err := l.query(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	l.cacheMutex.Lock()
+	if l.cache == nil {
+		l.cache = make(map[string]rancherAPICacheEntry)
+	}
+	l.cache[clusterID] = rancherAPICacheEntry{projects: projects, expires: now.Add(l.CacheTTL)}
+	l.cacheMutex.Unlock()
+
+	return projects, nil
+}
+
+func (l *RancherAPIProjectLister) query(ctx context.Context, clusterID string) ([]unstructured.Unstructured, error) {
+	endpoint, err := url.Parse(l.URL + "/v3/projects")
+	if err != nil {
+		return nil, fmt.Errorf("invalid Rancher URL: %w", err)
+	}
+	if clusterID != "" {
+		query := endpoint.Query()
+		query.Set("clusterId", clusterID)
+		endpoint.RawQuery = query.Encode()
+	}
+
+	var projects []unstructured.Unstructured
+	next := endpoint.String()
+	for next != "" {
+		page, err := l.queryPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range page.Data {
+			if project.State != "removing" {
+				projects = append(projects, project.toProject())
+			}
+		}
+		next = page.Pagination.Next
+	}
+	return projects, nil
+}
+
+func (l *RancherAPIProjectLister) queryPage(ctx context.Context, pageURL string) (*rancherProjectCollection, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if l.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+l.Token)
+	}
+
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query Rancher API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Rancher API returned status %d", resp.StatusCode)
+	}
+
+	var page rancherProjectCollection
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("unable to decode Rancher API response: %w", err)
+	}
+	return &page, nil
+}
+
+// toProject converts the v3 project into the shape of a Project object
+func (p rancherAPIProject) toProject() unstructured.Unstructured {
+	project := unstructured.Unstructured{}
+	project.SetAPIVersion("management.cattle.io/v3")
+	project.SetKind("Project")
+
+	// v3 project IDs have the form <clusterId>:<projectId>
+	name := p.ID
+	if _, projectID, found := strings.Cut(p.ID, ":"); found {
+		name = projectID
+	}
+	project.SetName(name)
+	project.SetNamespace(p.ClusterID)
+	project.SetLabels(p.Labels)
+	project.SetAnnotations(p.Annotations)
+	if created, err := time.Parse(time.RFC3339, p.Created); err == nil {
+		project.SetCreationTimestamp(metav1.NewTime(created))
+	}
+	_ = unstructured.SetNestedField(project.Object, p.Name, "spec", "displayName")
+	_ = unstructured.SetNestedField(project.Object, p.ClusterID, "spec", "clusterName")
+	return project
+}
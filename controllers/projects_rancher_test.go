@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newRancherAPIServer serves the projects as a single page of the v3 API
+func newRancherAPIServer(t *testing.T, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v3/projects" || req.URL.Query().Get("name") != "" {
+			t.Errorf("unexpected request %s", req.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRancherAPIProjectListerMatching(t *testing.T) {
+	server := newRancherAPIServer(t, `{"data": [
+		{"id": "c-abc:p-b", "name": "Payments", "clusterId": "c-abc", "state": "active"},
+		{"id": "c-abc:p-a", "name": "payments", "clusterId": "c-abc", "state": "active"},
+		{"id": "c-abc:p-c", "name": "Orders", "clusterId": "c-abc", "state": "active"},
+		{"id": "c-abc:p-d", "name": "Billing", "clusterId": "c-abc", "state": "removing"}
+	]}`)
+
+	tests := []struct {
+		name    string
+		policy  AmbiguityPolicy
+		owner   string
+		want    string
+		wantErr bool
+	}{
+		{name: "case-insensitive", policy: AmbiguityPolicyFail, owner: "orders", want: "p-c"},
+		{name: "ambiguous fails", policy: AmbiguityPolicyFail, owner: "payments", wantErr: true},
+		{name: "ambiguous first", policy: AmbiguityPolicyFirst, owner: "payments", want: "p-a"},
+		{name: "removing", policy: AmbiguityPolicyFail, owner: "billing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler()
+			r.ProjectLister = NewRancherAPIProjectLister(server.URL, "token")
+			r.AmbiguityPolicy = tt.policy
+
+			project, err := r.findProjectByName(context.Background(), tt.owner, "c-abc")
+			var ambiguous *ambiguousProjectError
+			if tt.wantErr != errors.As(err, &ambiguous) {
+				t.Fatalf("findProjectByName() error = %v, want ambiguous: %v", err, tt.wantErr)
+			}
+			got := ""
+			if project != nil {
+				got = project.GetName()
+			}
+			if got != tt.want {
+				t.Errorf("findProjectByName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// allowProtectedProjectAnnotation lets a namespace be assigned to a protected project
+const allowProtectedProjectAnnotation = "rancher-operator.quiknode.io/allow-protected-project"
+
+// DefaultProtectedProjects are the Rancher built-in projects that should not
+// receive application namespaces by accident
+var DefaultProtectedProjects = []string{"System", "Default"}
+
+// isProtectedProject reports whether the resolved project is in the protected
+// list, comparing its display name or, for external references, the owner
+func (r *NamespaceReconciler) isProtectedProject(ref *ProjectRef, owner string) bool {
+	name := owner
+	if ref.Project != nil {
+		if displayName, found, err := unstructured.NestedString(ref.Project.Object, "spec", "displayName"); err == nil && found {
+			name = displayName
+		}
+	}
+
+	for _, protected := range r.ProtectedProjects {
+		if strings.EqualFold(name, protected) {
+			return true
+		}
+	}
+	return false
+}
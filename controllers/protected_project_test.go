@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestIsProtectedProject(t *testing.T) {
+	r := &NamespaceReconciler{ProtectedProjects: DefaultProtectedProjects}
+
+	if !r.isProtectedProject(&ProjectRef{Project: newProject("local", "p-system", "system")}, "payments") {
+		t.Error("isProtectedProject() should match the display name case-insensitively")
+	}
+	if r.isProtectedProject(&ProjectRef{Project: newProject("local", "p-live", "payments")}, "system") {
+		t.Error("isProtectedProject() should judge a management project by its display name, not the owner")
+	}
+	// External resolvers return no project object, so the owner is compared
+	if !r.isProtectedProject(&ProjectRef{ProjectID: "p-default"}, "Default") {
+		t.Error("isProtectedProject() should compare the owner of external references")
+	}
+}
+
+func TestReconcileRequiresOptInForProtectedProject(t *testing.T) {
+	ctx := context.Background()
+	optedIn := newNamespace("agents-system", map[string]string{appOwnerLabel: "System"})
+	optedIn.Annotations = map[string]string{allowProtectedProjectAnnotation: "true"}
+	r := newTestReconciler(
+		newProject("local", "p-system", "System"),
+		newNamespace("agents", map[string]string{appOwnerLabel: "System"}),
+		optedIn,
+	)
+
+	for name, wantProjectID := range map[string]string{"agents": "", "agents-system": "p-system"} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			t.Fatal(err)
+		}
+		if got := namespace.Labels[rancherProjectIDLabel]; got != wantProjectID {
+			t.Errorf("namespace %s projectId = %q, want %q", name, got, wantProjectID)
+		}
+	}
+}
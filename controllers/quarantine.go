@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// quarantinedAnnotation stops the operator from patching a namespace until a
+// user removes it. Its value is the time the namespace was quarantined.
+const quarantinedAnnotation = "rancher-operator.quiknode.io/quarantined"
+
+// patchFailures counts consecutive failed patches per namespace
+type patchFailures struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+// fail records a failed patch and returns the consecutive failure count
+func (p *patchFailures) fail(clusterID, namespace string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.counts == nil {
+		p.counts = make(map[string]int)
+	}
+	key := clusterID + "/" + namespace
+	p.counts[key]++
+	return p.counts[key]
+}
+
+// count returns the consecutive failure count
+func (p *patchFailures) count(clusterID, namespace string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.counts[clusterID+"/"+namespace]
+}
+
+// reset clears the failure count after a successful patch or quarantine
+func (p *patchFailures) reset(clusterID, namespace string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.counts, clusterID+"/"+namespace)
+}
+
+// recordPatchFailure counts a failed patch and quarantines the namespace once
+// QuarantineThreshold consecutive patches have failed
+func (r *NamespaceReconciler) recordPatchFailure(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace, clusterID string) {
+	if r.QuarantineThreshold <= 0 {
+		return
+	}
+
+	failures := r.patchFailures.fail(clusterID, namespace.Name)
+	if failures < r.QuarantineThreshold {
+		return
+	}
+
+	logger := log.FromContext(ctx)
+	patch := client.MergeFrom(namespace.DeepCopy())
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+	namespace.Annotations[quarantinedAnnotation] = r.clock().Now().UTC().Format(time.RFC3339)
+	if err := namespaceClient.Patch(ctx, namespace, patch); err != nil {
+		logger.Error(err, "unable to quarantine namespace", "namespace", namespace.Name, "clusterId", clusterID)
+		return
+	}
+
+	r.patchFailures.reset(clusterID, namespace.Name)
+	logger.Info("quarantined namespace after repeated patch failures", "namespace", namespace.Name, "failures", failures, "clusterId", clusterID)
+	r.eventf(namespace, corev1.EventTypeWarning, "Quarantined",
+		"Project assignment failed %d times; remove the %s annotation to retry", failures, quarantinedAnnotation)
+}
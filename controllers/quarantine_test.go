@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileQuarantinesAfterRepeatedPatchFailures(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	r.QuarantineThreshold = 3
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	// Every patch assigning a project fails; other patches go through
+	assignments := 0
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newProject("local", "p-payments", "payments"), newNamespace("checkout", map[string]string{appOwnerLabel: "payments"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				data, err := patch.Data(obj)
+				if err != nil {
+					return err
+				}
+				if strings.Contains(string(data), rancherProjectIDLabel) {
+					assignments++
+					return apierrors.NewInternalError(errors.New("etcdserver: request timed out"))
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}
+
+	for attempt := 1; attempt <= r.QuarantineThreshold; attempt++ {
+		if _, err := r.Reconcile(ctx, req); err == nil {
+			t.Fatalf("Reconcile() attempt %d error = nil, want the patch error", attempt)
+		}
+		got := &corev1.Namespace{}
+		if err := r.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatalf("get namespace: %v", err)
+		}
+		_, quarantined := got.Annotations[quarantinedAnnotation]
+		if want := attempt == r.QuarantineThreshold; quarantined != want {
+			t.Fatalf("after attempt %d quarantined = %v, want %v", attempt, quarantined, want)
+		}
+	}
+
+	// A quarantined namespace is not patched again
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() of quarantined namespace error = %v", err)
+	}
+	if assignments != r.QuarantineThreshold {
+		t.Errorf("assignment patches = %d, want %d", assignments, r.QuarantineThreshold)
+	}
+
+	var quarantinedEvents int
+	for len(recorder.Events) > 0 {
+		if strings.HasPrefix(<-recorder.Events, corev1.EventTypeWarning+" Quarantined") {
+			quarantinedEvents++
+		}
+	}
+	if quarantinedEvents != 1 {
+		t.Errorf("Quarantined events = %d, want 1", quarantinedEvents)
+	}
+}
+
+func TestPatchFailuresResetClearsCount(t *testing.T) {
+	var failures patchFailures
+	failures.fail("local", "checkout")
+	if got := failures.fail("local", "checkout"); got != 2 {
+		t.Fatalf("fail() = %d, want 2", got)
+	}
+	if got := failures.fail("c-other", "checkout"); got != 1 {
+		t.Errorf("fail() on another cluster = %d, want 1", got)
+	}
+
+	failures.reset("local", "checkout")
+	if got := failures.count("local", "checkout"); got != 0 {
+		t.Errorf("count() after reset = %d, want 0", got)
+	}
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// newDatedProject returns a payments project created at the given time
+func newDatedProject(name string, created time.Time) *unstructured.Unstructured {
+	project := newProject("local", name, "payments")
+	project.SetCreationTimestamp(metav1.NewTime(created))
+	return project
+}
+
+func TestSelectProjectRecency(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		candidates []*unstructured.Unstructured
+		want       string
+	}{
+		{
+			name: "newest wins",
+			candidates: []*unstructured.Unstructured{
+				newDatedProject("p-a", base),
+				newDatedProject("p-c", base.Add(time.Hour)),
+				newDatedProject("p-b", base.Add(time.Minute)),
+			},
+			want: "p-c",
+		},
+		{
+			name: "same second keeps the lowest ID",
+			candidates: []*unstructured.Unstructured{
+				newDatedProject("p-z", base.Add(time.Hour)),
+				newDatedProject("p-y", base.Add(time.Hour)),
+				newDatedProject("p-a", base),
+			},
+			want: "p-y",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler()
+			r.AmbiguityPolicy = AmbiguityPolicyRecency
+
+			project, err := r.selectProject(context.Background(), tt.candidates, "payments")
+			if err != nil {
+				t.Fatalf("selectProject() error = %v", err)
+			}
+			if project.GetName() != tt.want {
+				t.Errorf("selectProject() = %s, want %s", project.GetName(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileRecencyPolicyAssignsNewestProject(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	// The newer project sorts last, so only the recency policy picks it
+	r := newTestReconciler(
+		newDatedProject("p-first", base),
+		newDatedProject("p-second", base.Add(24*time.Hour)),
+		newNamespace("checkout", map[string]string{appOwnerLabel: "payments"}),
+	)
+	r.AmbiguityPolicy = AmbiguityPolicyRecency
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	got := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+		t.Fatalf("get namespace: %v", err)
+	}
+	if got.Labels[rancherProjectIDLabel] != "p-second" {
+		t.Errorf("project label = %q, want p-second", got.Labels[rancherProjectIDLabel])
+	}
+	if policy, err := ParseAmbiguityPolicy("recency"); err != nil || policy != AmbiguityPolicyRecency {
+		t.Errorf("ParseAmbiguityPolicy(\"recency\") = (%q, %v)", policy, err)
+	}
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestResolutionAnnotationDescribesAssignment(t *testing.T) {
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+
+	for _, tt := range []struct {
+		name       string
+		annotation string
+		want       *projectResolution
+	}{
+		{name: "disabled by default", annotation: ""},
+		{name: "enabled", annotation: DefaultResolutionAnnotation,
+			want: &projectResolution{Owner: "payments", ProjectID: "p-live", ClusterID: "c-abc"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(newProject("c-abc", "p-live", "payments"), newNamespace("payments", map[string]string{appOwnerLabel: "payments"}))
+			r.ResolutionAnnotation = tt.annotation
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			namespace := &corev1.Namespace{}
+			if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+				t.Fatal(err)
+			}
+			value, ok := namespace.Annotations[DefaultResolutionAnnotation]
+			if tt.want == nil {
+				if ok {
+					t.Errorf("resolution annotation = %s, want none", value)
+				}
+				return
+			}
+
+			// Admission policies parse the value, so it must be the documented JSON object
+			var got projectResolution
+			if err := json.Unmarshal([]byte(value), &got); err != nil {
+				t.Fatalf("resolution annotation %q is not JSON: %v", value, err)
+			}
+			if got != *tt.want {
+				t.Errorf("resolution annotation = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
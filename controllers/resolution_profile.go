@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Supported resolution profile match modes
+const (
+	// MatchModeAny matches the display name, aliases, labels and annotations
+	MatchModeAny = "any"
+	// MatchModeDisplayName matches spec.displayName only
+	MatchModeDisplayName = "displayName"
+	// MatchModeID matches the Project object name, for example p-abc12
+	MatchModeID = "id"
+)
+
+// ResolutionProfile describes one way of resolving a namespace to a project.
+// Profiles are evaluated in order and the first one that resolves wins.
+type ResolutionProfile struct {
+	Name string `json:"name"`
+	// OwnerLabel is the namespace label holding the owner, defaults to appOwner
+	OwnerLabel string `json:"ownerLabel,omitempty"`
+	// MatchMode selects how the owner is compared against projects
+	MatchMode string `json:"matchMode,omitempty"`
+	// ProjectSelector is a label selector restricting candidate projects
+	ProjectSelector string `json:"projectSelector,omitempty"`
+
+	selector labels.Selector
+}
+
+// ParseResolutionProfiles parses a JSON list of profiles, for example
+// [{"name":"team","ownerLabel":"team","matchMode":"displayName","projectSelector":"tier=prod"}]
+func ParseResolutionProfiles(spec string) ([]ResolutionProfile, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var profiles []ResolutionProfile
+	if err := json.Unmarshal([]byte(spec), &profiles); err != nil {
+		return nil, fmt.Errorf("unable to parse resolution profiles: %w", err)
+	}
+
+	for i := range profiles {
+		p := &profiles[i]
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("profile-%d", i)
+		}
+		if p.OwnerLabel == "" {
+			p.OwnerLabel = appOwnerLabel
+		}
+		switch p.MatchMode {
+		case "":
+			p.MatchMode = MatchModeAny
+		case MatchModeAny, MatchModeDisplayName, MatchModeID:
+		default:
+			return nil, fmt.Errorf("resolution profile %q: unknown match mode %q", p.Name, p.MatchMode)
+		}
+		selector, err := labels.Parse(p.ProjectSelector)
+		if err != nil {
+			return nil, fmt.Errorf("resolution profile %q: invalid project selector: %w", p.Name, err)
+		}
+		p.selector = selector
+	}
+
+	return profiles, nil
+}
+
+// matches reports whether the project is selected by the profile and matches the owner
+func (p *ResolutionProfile) matches(r *NamespaceReconciler, project *unstructured.Unstructured, owner string) bool {
+	if p.selector != nil && !p.selector.Matches(labels.Set(project.GetLabels())) {
+		return false
+	}
+
+	switch p.MatchMode {
+	case MatchModeDisplayName:
+		displayName, _, _ := unstructured.NestedString(project.Object, "spec", "displayName")
+		return displayName != "" && r.namesMatch(displayName, owner)
+	case MatchModeID:
+		return project.GetName() == owner
+	default:
+		return r.projectMatches(project, owner)
+	}
+}
+
+// resolveWithProfiles walks the configured profiles in order and returns the
+// owner and project of the first profile that resolves. An empty owner means
+// no profile found an owner label on the namespace.
+func (r *NamespaceReconciler) resolveWithProfiles(ctx context.Context, namespace *corev1.Namespace, clusterID string, behavior Behavior) (string, *ProjectRef, error) {
+	logger := log.FromContext(ctx)
+	ctx = withNamespace(ctx, namespace)
+
+	var firstOwner string
+	var ineligibleErr error
+	for i := range r.ResolutionProfiles {
+		profile := &r.ResolutionProfiles[i]
+
+		owner := r.normalizeOwner(namespace.Labels[profile.OwnerLabel], behavior)
+		if owner == "" {
+			continue
+		}
+		if firstOwner == "" {
+			firstOwner = owner
+		}
+
+		project, err := r.findProject(ctx, owner, clusterID, func(project *unstructured.Unstructured) bool {
+			return profile.matches(r, project, owner)
+		})
+		if isProjectIneligible(err) {
+			// A later profile may still resolve to an eligible project
+			ineligibleErr = err
+			continue
+		}
+		if err != nil {
+			return owner, nil, err
+		}
+		if project == nil {
+			logger.V(1).Info("resolution profile did not resolve", "profile", profile.Name, "owner", owner, "clusterId", clusterID)
+			continue
+		}
+
+		logger.V(1).Info("resolution profile resolved", "profile", profile.Name, "owner", owner, "projectId", project.GetName())
+		ref := r.projectRef(project, owner)
+		ref.Source = SourceProfile
+		return owner, ref, nil
+	}
+
+	return firstOwner, nil, ineligibleErr
+}
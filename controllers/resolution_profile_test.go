@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolutionProfilesEvaluatedInOrder(t *testing.T) {
+	profiles, err := ParseResolutionProfiles(`[
+		{"name":"team","ownerLabel":"team","matchMode":"displayName","projectSelector":"tier=prod"},
+		{"name":"ledger","ownerLabel":"ledger-project","matchMode":"id"}
+	]`)
+	if err != nil {
+		t.Fatalf("ParseResolutionProfiles() error = %v", err)
+	}
+
+	staging := newProject("local", "p-staging", "payments")
+	staging.SetLabels(map[string]string{"tier": "staging"})
+	prod := newProject("local", "p-prod", "search")
+	prod.SetLabels(map[string]string{"tier": "prod"})
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		wantOwner   string
+		wantProject string
+	}{
+		{
+			// The team project is filtered out by the first profile's selector
+			name:        "second profile resolves",
+			labels:      map[string]string{"team": "payments", "ledger-project": "p-shared"},
+			wantOwner:   "p-shared",
+			wantProject: "p-shared",
+		},
+		{
+			name:        "first profile wins",
+			labels:      map[string]string{"team": "search", "ledger-project": "p-shared"},
+			wantOwner:   "search",
+			wantProject: "p-prod",
+		},
+		{
+			name:      "no profile resolves",
+			labels:    map[string]string{"team": "payments"},
+			wantOwner: "payments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(staging, prod, newProject("local", "p-shared", "shared services"))
+			r.ResolutionProfiles = profiles
+
+			res, err := r.resolveNamespace(context.Background(), r.Client, newNamespace("payments", tt.labels), "local")
+			if err != nil {
+				t.Fatalf("resolveNamespace() error = %v", err)
+			}
+			gotProject := ""
+			if res.Ref != nil {
+				gotProject = res.Ref.ProjectID
+			}
+			if res.Owner != tt.wantOwner || gotProject != tt.wantProject {
+				t.Errorf("resolveNamespace() = (%q, %q), want (%q, %q)", res.Owner, gotProject, tt.wantOwner, tt.wantProject)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package controllers
+
+// ResolutionSource names what produced a namespace's project match
+type ResolutionSource string
+
+const (
+	// SourceOwnerMapping means an OwnerMapping resource names the project
+	SourceOwnerMapping ResolutionSource = "ownerMapping"
+	// SourceOwnerApp means the namespace is owned by a Rancher App
+	SourceOwnerApp ResolutionSource = "app"
+	// SourceServiceAccount means the creating service account is mapped to the project
+	SourceServiceAccount ResolutionSource = "serviceAccount"
+	// SourceProfile means a resolution profile matched the project
+	SourceProfile ResolutionSource = "profile"
+	// SourceCostCenter means the cost-center label is mapped to the project
+	SourceCostCenter ResolutionSource = "costCenter"
+	// SourcePool means the project was picked from the project pool by namespace name
+	SourcePool ResolutionSource = "pool"
+	// SourceResolver means the configured external resolver returned the project
+	SourceResolver ResolutionSource = "resolver"
+	// SourceTemplate means the project name was rendered from the owner
+	SourceTemplate ResolutionSource = "template"
+	// SourceLabel means the owner came from an owner label
+	SourceLabel ResolutionSource = "label"
+	// SourceAnnotation means the owner came from the owner annotation
+	SourceAnnotation ResolutionSource = "annotation"
+	// SourceConfigMap means the owner came from the owner ConfigMap in the namespace
+	SourceConfigMap ResolutionSource = "configmap"
+	// SourceFleet means the owner came from the Fleet bundle label
+	SourceFleet ResolutionSource = "fleet"
+	// SourceDisplayName means the owner is the Rancher display name of the namespace
+	SourceDisplayName ResolutionSource = "displayName"
+	// SourceParent means the owner was inherited from the HNC parent namespace
+	SourceParent ResolutionSource = "parent"
+
+	// resolutionSourceAnnotation records the resolution source on the namespace
+	resolutionSourceAnnotation = "rancher-operator.quiknode.io/resolution-source"
+)
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileAnnotatesResolutionSource(t *testing.T) {
+	const ownerAnnotation = "example.io/owner"
+	const serviceAccountAnnotation = "example.io/created-by"
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		template    string
+		disabled    bool
+		want        string
+	}{
+		{name: "owner label", labels: map[string]string{appOwnerLabel: "payments"}, want: string(SourceLabel)},
+		{name: "owner annotation", annotations: map[string]string{ownerAnnotation: "payments"}, want: string(SourceAnnotation)},
+		{name: "service account", annotations: map[string]string{serviceAccountAnnotation: "system:serviceaccount:ci:deployer"}, want: string(SourceServiceAccount)},
+		{name: "name template", labels: map[string]string{appOwnerLabel: "pay"}, template: "{{ .Owner }}ments", want: string(SourceTemplate)},
+		{name: "disabled", labels: map[string]string{appOwnerLabel: "payments"}, disabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			namespace := newNamespace("checkout", tt.labels)
+			namespace.Annotations = tt.annotations
+			r := newTestReconciler(newProject("local", "p-payments", "payments"), namespace)
+			r.AnnotateResolutionSource = !tt.disabled
+			r.OwnerAnnotation = ownerAnnotation
+			r.ServiceAccountAnnotation = serviceAccountAnnotation
+			r.ServiceAccountProjects = map[string]string{"system:serviceaccount:ci:deployer": "payments"}
+			if tt.template != "" {
+				tmpl, err := ParseProjectNameTemplate(tt.template)
+				if err != nil {
+					t.Fatalf("ParseProjectNameTemplate() error = %v", err)
+				}
+				r.ProjectNameTemplate = tmpl
+			}
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != "p-payments" {
+				t.Fatalf("project label = %q, want p-payments", got.Labels[rancherProjectIDLabel])
+			}
+			if source := got.Annotations[resolutionSourceAnnotation]; source != tt.want {
+				t.Errorf("%s = %q, want %q", resolutionSourceAnnotation, source, tt.want)
+			}
+		})
+	}
+}
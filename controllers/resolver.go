@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ProjectRef identifies the Rancher project a namespace should be assigned to
+type ProjectRef struct {
+	ProjectID string
+	ClusterID string
+	// ProjectName is set by resolvers that only know the project display
+	// name. The name is then matched against Rancher Projects.
+	ProjectName string
+	// Confidence describes how the owner matched the project
+	Confidence MatchConfidence
+	// Source names what produced the match. References resolved from an
+	// owner value leave it empty and take the owner's source.
+	Source ResolutionSource
+	// Project is the Rancher Project object when resolved from the management
+	// cluster. It is nil for references returned by external resolvers.
+	Project *unstructured.Unstructured
+}
+
+// copy returns a shallow copy of the reference, or nil for a nil reference
+func (ref *ProjectRef) copy() *ProjectRef {
+	if ref == nil {
+		return nil
+	}
+	c := *ref
+	return &c
+}
+
+// ProjectResolver maps an owner value to a Rancher project. Implementations
+// return a nil reference when they have no mapping for the owner.
+type ProjectResolver interface {
+	ResolveProject(ctx context.Context, owner, clusterID string) (*ProjectRef, error)
+}
+
+// NamespaceScopedResolver is implemented by project resolvers that match the
+// namespace itself rather than its owner. They are also consulted for
+// namespaces without an owner, which then receive an empty owner value.
+type NamespaceScopedResolver interface {
+	ProjectResolver
+	ResolvesWithoutOwner() bool
+}
+
+// resolvesWithoutOwner reports whether the configured resolver can resolve
+// namespaces that have no owner
+func (r *NamespaceReconciler) resolvesWithoutOwner(behavior Behavior) bool {
+	scoped, ok := r.ProjectResolver.(NamespaceScopedResolver)
+	return behavior == BehaviorV2 && ok && scoped.ResolvesWithoutOwner()
+}
+
+// resolveProject resolves the owner through the configured resolver and falls
+// back to matching Rancher Projects by name. The legacy v1 behavior only
+// matches Rancher Projects. An empty owner is only resolved by the resolver.
+func (r *NamespaceReconciler) resolveProject(ctx context.Context, namespace *corev1.Namespace, owner, clusterID string, behavior Behavior) (*ProjectRef, error) {
+	ctx = withNamespace(ctx, namespace)
+
+	// Derive the project name from the owner unless a resolver names the project
+	var projectName string
+	var source ResolutionSource
+	if owner != "" {
+		var err error
+		if projectName, err = r.projectNameFor(owner, namespace, clusterID); err != nil {
+			return nil, err
+		}
+		if r.ProjectNameTemplate != nil {
+			source = SourceTemplate
+		}
+	}
+
+	if behavior == BehaviorV2 && r.ProjectResolver != nil {
+		ref, err := r.ProjectResolver.ResolveProject(ctx, owner, clusterID)
+		if err != nil {
+			return nil, clusterError(clusterID, "external resolver failed for owner "+owner, err)
+		}
+		if ref != nil && ref.ProjectID == "" && ref.ProjectName != "" {
+			// The resolver only named the project, so match it like an owner
+			projectName = ref.ProjectName
+			source = SourceResolver
+		} else if ref != nil {
+			if ref.ClusterID == "" {
+				ref.ClusterID = r.extractClusterID(ref.ProjectID)
+			}
+			if ref.Confidence == "" {
+				ref.Confidence = ConfidenceHigh
+			}
+			ref.Source = SourceResolver
+			return ref, nil
+		}
+	}
+
+	if projectName == "" {
+		return nil, nil
+	}
+	project, err := r.findProjectByName(ctx, projectName, clusterID)
+	if err != nil || project == nil {
+		return nil, err
+	}
+	ref := r.projectRef(project, projectName)
+	ref.Source = source
+	return ref, nil
+}
+
+// projectRef builds the reference for a Rancher Project matched for owner
+func (r *NamespaceReconciler) projectRef(project *unstructured.Unstructured, owner string) *ProjectRef {
+	// Get project ID and cluster ID from the project
+	projectID := project.GetName()
+	projectClusterID := r.extractClusterID(projectID)
+
+	// Rancher stores each Project in the namespace named after its cluster, so a
+	// namespace on local can still be assigned to a downstream project
+	if projectClusterID == "" {
+		projectClusterID = project.GetNamespace()
+	}
+
+	return &ProjectRef{
+		ProjectID:  projectID,
+		ClusterID:  projectClusterID,
+		Confidence: r.matchConfidence(project, owner),
+		Project:    project,
+	}
+}
+
+// projectTerminating reports whether the Rancher Project is being deleted
+func projectTerminating(project *unstructured.Unstructured) bool {
+	return project.GetDeletionTimestamp() != nil
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type namespaceContextKey struct{}
+
+// withNamespace stores the namespace being reconciled in the context so
+// resolvers can inspect more than the owner value
+func withNamespace(ctx context.Context, namespace *corev1.Namespace) context.Context {
+	return context.WithValue(ctx, namespaceContextKey{}, namespace)
+}
+
+// NamespaceFromContext returns the namespace being reconciled, if any
+func NamespaceFromContext(ctx context.Context) (*corev1.Namespace, bool) {
+	namespace, ok := ctx.Value(namespaceContextKey{}).(*corev1.Namespace)
+	return namespace, ok
+}
+
+// CELProjectResolver evaluates a CEL expression over the namespace and returns
+// the name of the target project. The expression can use the variables
+// name, labels, annotations, owner and clusterId, for example
+// labels["team"] + "-" + labels["env"]. An empty result means no mapping.
+// Namespaces without an owner are evaluated with an empty owner.
+type CELProjectResolver struct {
+	program cel.Program
+}
+
+// NewCELProjectResolver compiles the expression and checks it returns a string
+func NewCELProjectResolver(expression string) (*CELProjectResolver, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("name", cel.StringType),
+		cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("annotations", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("owner", cel.StringType),
+		cel.Variable("clusterId", cel.StringType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("unable to compile CEL expression: %w", issues.Err())
+	}
+	if !ast.OutputType().IsExactType(cel.StringType) {
+		return nil, fmt.Errorf("CEL expression must return a string, got %s", ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build CEL program: %w", err)
+	}
+	return &CELProjectResolver{program: program}, nil
+}
+
+// ResolvesWithoutOwner implements NamespaceScopedResolver
+func (c *CELProjectResolver) ResolvesWithoutOwner() bool {
+	return true
+}
+
+// ResolveProject implements ProjectResolver
+func (c *CELProjectResolver) ResolveProject(ctx context.Context, owner, clusterID string) (*ProjectRef, error) {
+	namespace, ok := NamespaceFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	labels := namespace.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	annotations := namespace.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	out, _, err := c.program.ContextEval(ctx, map[string]any{
+		"name":        namespace.Name,
+		"labels":      labels,
+		"annotations": annotations,
+		"owner":       owner,
+		"clusterId":   clusterID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate CEL expression for namespace %s: %w", namespace.Name, err)
+	}
+
+	projectName, ok := out.Value().(string)
+	if !ok || projectName == "" {
+		return nil, nil
+	}
+	return &ProjectRef{ProjectName: projectName}, nil
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileResolvesProjectWithCELExpression(t *testing.T) {
+	ctx := context.Background()
+	resolver, err := NewCELProjectResolver(`has(labels.team) && has(labels.env) ? labels["team"] + "-" + labels["env"] : ""`)
+	if err != nil {
+		t.Fatalf("NewCELProjectResolver() error = %v", err)
+	}
+	// Neither namespace has an appOwner label; the expression alone selects the project
+	r := newTestReconciler(
+		newProject("local", "p-payments-prod", "payments-prod"),
+		newProject("local", "p-payments", "payments"),
+		newNamespace("checkout", map[string]string{"team": "payments", "env": "prod"}),
+		newNamespace("sandbox", map[string]string{"team": "payments"}),
+	)
+	r.ProjectResolver = resolver
+	// External resolvers are part of the v2 resolution behavior
+	r.DefaultBehavior = BehaviorV2
+
+	wantProject := map[string]string{"checkout": "p-payments-prod", "sandbox": ""}
+	for name, want := range wantProject {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			t.Fatalf("get namespace %s: %v", name, err)
+		}
+		if got := namespace.Labels[rancherProjectIDLabel]; got != want {
+			t.Errorf("%s project label = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNewCELProjectResolverRejectsInvalidExpressions(t *testing.T) {
+	for _, expression := range []string{
+		`labels["team"] + `,
+		`size(labels)`,
+		`unknown["team"]`,
+	} {
+		if _, err := NewCELProjectResolver(expression); err == nil {
+			t.Errorf("NewCELProjectResolver(%q) succeeded, want an error", expression)
+		}
+	}
+}
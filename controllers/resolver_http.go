@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+const (
+	// Defaults for the HTTP project resolver
+	defaultHTTPResolverTimeout  = 5 * time.Second
+	defaultHTTPResolverCacheTTL = 5 * time.Minute
+)
+
+// HTTPProjectResolver resolves owners by querying an external mapping service.
+// It sends GET <endpoint>?owner=<owner>&clusterId=<clusterId> and expects a
+// JSON body of the form {"projectId": "...", "clusterId": "..."}. A 404
+// response means the service has no mapping for the owner.
+type HTTPProjectResolver struct {
+	Endpoint string
+	CacheTTL time.Duration
+	Client   *http.Client
+	// Clock times cache expiry and defaults to the real clock
+	Clock clock.WithTicker
+
+	cache resolverCache
+}
+
+// resolverCache caches resolver answers, including misses, for a TTL.
+// Callers get their own copy of a cached reference because resolveProject
+// fills in its ClusterID, Confidence and Source.
+type resolverCache struct {
+	mutex   sync.Mutex
+	entries map[string]httpResolverCacheEntry
+}
+
+type httpResolverCacheEntry struct {
+	ref     *ProjectRef
+	expires time.Time
+}
+
+// get returns a copy of the unexpired reference cached under key
+func (c *resolverCache) get(key string, now time.Time) (*ProjectRef, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.ref.copy(), true
+}
+
+// set caches a copy of ref under key and prunes expired entries, so owners
+// that are no longer reconciled do not stay in memory
+func (c *resolverCache) set(key string, ref *ProjectRef, ttl time.Duration, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]httpResolverCacheEntry)
+	}
+	for cachedKey, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, cachedKey)
+		}
+	}
+	c.entries[key] = httpResolverCacheEntry{ref: ref.copy(), expires: now.Add(ttl)}
+}
+
+type httpResolverResponse struct {
+	ProjectID string `json:"projectId"`
+	ClusterID string `json:"clusterId"`
+}
+
+// NewHTTPProjectResolver creates a resolver for the given endpoint with default timeout and cache TTL
+func NewHTTPProjectResolver(endpoint string) *HTTPProjectResolver {
+	return &HTTPProjectResolver{
+		Endpoint: endpoint,
+		CacheTTL: defaultHTTPResolverCacheTTL,
+		Client:   &http.Client{Timeout: defaultHTTPResolverTimeout},
+	}
+}
+
+// ResolveProject implements ProjectResolver
+func (h *HTTPProjectResolver) ResolveProject(ctx context.Context, owner, clusterID string) (*ProjectRef, error) {
+	key := clusterID + "/" + owner
+
+	if ref, cached := h.cache.get(key, clockOrReal(h.Clock).Now()); cached {
+		return ref, nil
+	}
+
+	ref, err := h.query(ctx, owner, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	h.cache.set(key, ref, h.CacheTTL, clockOrReal(h.Clock).Now())
+	return ref.copy(), nil
+}
+
+func (h *HTTPProjectResolver) query(ctx context.Context, owner, clusterID string) (*ProjectRef, error) {
+	endpoint, err := url.Parse(h.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolver endpoint: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("owner", owner)
+	query.Set("clusterId", clusterID)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query project resolver: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("project resolver returned status %d", resp.StatusCode)
+	}
+
+	var body httpResolverResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("unable to decode project resolver response: %w", err)
+	}
+	if body.ProjectID == "" {
+		return nil, nil
+	}
+
+	return &ProjectRef{ProjectID: body.ProjectID, ClusterID: body.ClusterID}, nil
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolverCacheReturnsCopies(t *testing.T) {
+	now := time.Now()
+	cache := resolverCache{}
+	cache.set("local/payments", &ProjectRef{ProjectID: "p-live"}, time.Minute, now)
+
+	ref, ok := cache.get("local/payments", now)
+	if !ok {
+		t.Fatal("get() missed a cached reference")
+	}
+	// resolveProject fills these in on the returned reference
+	ref.ClusterID = "c-abc"
+	ref.Confidence = ConfidenceExact
+
+	again, _ := cache.get("local/payments", now)
+	if again.ClusterID != "" || again.Confidence != "" {
+		t.Errorf("cached reference was mutated through a returned copy: %+v", again)
+	}
+}
+
+func TestResolverCacheExpiry(t *testing.T) {
+	now := time.Now()
+	cache := resolverCache{}
+	cache.set("local/payments", &ProjectRef{ProjectID: "p-live"}, time.Minute, now)
+	cache.set("local/unknown", nil, time.Minute, now)
+
+	if ref, ok := cache.get("local/unknown", now); !ok || ref != nil {
+		t.Errorf("get() = %v, %v, want a cached miss", ref, ok)
+	}
+	if _, ok := cache.get("local/payments", now.Add(time.Minute)); ok {
+		t.Error("get() returned an expired reference")
+	}
+
+	cache.set("local/billing", &ProjectRef{ProjectID: "p-billing"}, time.Minute, now.Add(2*time.Minute))
+	if len(cache.entries) != 1 {
+		t.Errorf("set() kept %d entries, want expired entries pruned", len(cache.entries))
+	}
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultNamespaceSelectorAnnotation is the default project annotation holding
+// a label selector for the namespaces that belong to the project
+const DefaultNamespaceSelectorAnnotation = "rancher-operator.quiknode.io/namespace-selector"
+
+// NamespaceSelectorResolver assigns a namespace to the project whose
+// namespace selector annotation matches the namespace's labels. The owner
+// value is not used for matching, so namespaces without an owner resolve too.
+// Projects with a malformed selector are logged and skipped.
+type NamespaceSelectorResolver struct {
+	// Reconciler provides the project list, served from the project cache when enabled
+	Reconciler *NamespaceReconciler
+	// Annotation is the project annotation holding the label selector
+	Annotation string
+}
+
+// ResolvesWithoutOwner implements NamespaceScopedResolver
+func (s *NamespaceSelectorResolver) ResolvesWithoutOwner() bool {
+	return true
+}
+
+// ResolveProject implements ProjectResolver
+func (s *NamespaceSelectorResolver) ResolveProject(ctx context.Context, owner, clusterID string) (*ProjectRef, error) {
+	namespace, ok := NamespaceFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	projects, _, err := s.Reconciler.listProjects(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceLabels := labels.Set(namespace.Labels)
+	var candidates []*unstructured.Unstructured
+	for i := range projects {
+		project := &projects[i]
+		value := project.GetAnnotations()[s.Annotation]
+		if value == "" || !s.Reconciler.projectEligible(project) {
+			continue
+		}
+		selector, err := labels.Parse(value)
+		if err != nil {
+			// One misconfigured project must not block resolution of every namespace
+			log.FromContext(ctx).Error(err, "ignoring project with an invalid namespace selector", "projectId", project.GetName(), "annotation", s.Annotation)
+			continue
+		}
+		if !selector.Empty() && selector.Matches(namespaceLabels) {
+			candidates = append(candidates, project)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	project, err := s.Reconciler.selectProject(ctx, candidates, namespace.Name)
+	if err != nil {
+		return nil, err
+	}
+	ref := s.Reconciler.projectRef(project, owner)
+	ref.Confidence = ConfidenceHigh
+	return ref, nil
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// newSelectorProject returns a project selecting namespaces with the selector
+func newSelectorProject(name, selector string) *unstructured.Unstructured {
+	project := newProject("local", name, name)
+	project.SetAnnotations(map[string]string{DefaultNamespaceSelectorAnnotation: selector})
+	return project
+}
+
+func TestReconcileAssignsProjectBySelector(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{name: "matching selector without owner", labels: map[string]string{"env": "prod"}, want: "p-prod"},
+		{name: "set based selector", labels: map[string]string{"env": "staging", "tier": "web"}, want: "p-staging-web"},
+		{name: "no matching selector", labels: map[string]string{"env": "dev"}},
+		{name: "no labels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(
+				newSelectorProject("p-prod", "env=prod"),
+				newSelectorProject("p-staging-web", "env=staging,tier in (web,edge)"),
+				// Neither a malformed nor an empty selector may block resolution
+				newSelectorProject("p-broken", "env in ("),
+				newSelectorProject("p-everything", ""),
+				newNamespace("checkout", tt.labels),
+			)
+			r.DefaultBehavior = BehaviorV2
+			r.ProjectResolver = &NamespaceSelectorResolver{Reconciler: r, Annotation: DefaultNamespaceSelectorAnnotation}
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != tt.want {
+				t.Errorf("project label = %q, want %q", got.Labels[rancherProjectIDLabel], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+// isManagementCluster reports whether a Rancher Cluster object describes the
+// management cluster itself, which some installations register under an ID
+// other than "local". The cluster matches when its status.apiEndpoint is the
+// API server the operator talks to directly, or when its status.caCert is the
+// CA of that API server.
+func (r *NamespaceReconciler) isManagementCluster(cluster *unstructured.Unstructured) bool {
+	if r.Manager == nil {
+		return false
+	}
+	config := r.Manager.GetConfig()
+	if config == nil {
+		return false
+	}
+
+	endpoint, _, _ := unstructured.NestedString(cluster.Object, "status", "apiEndpoint")
+	if endpoint != "" && sameEndpoint(endpoint, config.Host) {
+		return true
+	}
+
+	caCert, _, _ := unstructured.NestedString(cluster.Object, "status", "caCert")
+	if caCert == "" {
+		return false
+	}
+	clusterCA, err := base64.StdEncoding.DecodeString(caCert)
+	if err != nil {
+		// Older Rancher versions store the PEM without encoding
+		clusterCA = []byte(caCert)
+	}
+	managementCA := managementCAData(config)
+	return len(managementCA) > 0 && bytes.Equal(bytes.TrimSpace(clusterCA), bytes.TrimSpace(managementCA))
+}
+
+// sameEndpoint compares two API server URLs by host and port, ignoring paths
+// and defaulting the port to 443
+func sameEndpoint(a, b string) bool {
+	hostA, ok := endpointHost(a)
+	if !ok {
+		return false
+	}
+	hostB, ok := endpointHost(b)
+	return ok && strings.EqualFold(hostA, hostB)
+}
+
+func endpointHost(endpoint string) (string, bool) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "", false
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), true
+}
+
+// managementCAData returns the CA bundle of the management API server, read
+// from the CA file for in-cluster configs
+func managementCAData(config *rest.Config) []byte {
+	if len(config.CAData) > 0 {
+		return config.CAData
+	}
+	if config.CAFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(config.CAFile)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+const testManagementCA = "-----BEGIN CERTIFICATE-----\nTUFOQUdFTUVOVA==\n-----END CERTIFICATE-----\n"
+
+// newSelfCluster returns a not ready cluster reporting the given status field
+func newSelfCluster(name, field, value string) *unstructured.Unstructured {
+	cluster := newRancherCluster(name, "False")
+	_ = unstructured.SetNestedField(cluster.Object, value, "status", field)
+	return cluster
+}
+
+func TestRefreshReusesManagementClientForSelfCluster(t *testing.T) {
+	r := newTestReconciler(
+		newSelfCluster("c-endpoint", "apiEndpoint", "https://10.0.0.1:6443/k8s/clusters/c-endpoint"),
+		newSelfCluster("c-ca", "caCert", base64.StdEncoding.EncodeToString([]byte(testManagementCA))),
+		newSelfCluster("c-pem", "caCert", "\n"+testManagementCA),
+		newSelfCluster("c-other-endpoint", "apiEndpoint", "https://10.0.0.2:6443"),
+		newSelfCluster("c-other-ca", "caCert", base64.StdEncoding.EncodeToString([]byte("other"))),
+	)
+	r.Manager = &fakeManager{config: &rest.Config{Host: "https://10.0.0.1:6443", TLSClientConfig: rest.TLSClientConfig{CAData: []byte(testManagementCA)}}}
+
+	r.doRefreshClusterClients(context.Background())
+
+	// Other clusters are not ready, so only the self cluster aliases get a client
+	for _, clusterID := range []string{"c-endpoint", "c-ca", "c-pem"} {
+		if r.clusterClients[clusterID] != r.Client {
+			t.Errorf("client for %s is not the management client", clusterID)
+		}
+	}
+	for _, clusterID := range []string{"c-other-endpoint", "c-other-ca"} {
+		if _, ok := r.clusterClients[clusterID]; ok {
+			t.Errorf("%s got a client, want none for a not ready downstream cluster", clusterID)
+		}
+	}
+}
+
+func TestSameEndpoint(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "https://10.0.0.1:6443", b: "https://10.0.0.1:6443/", want: true},
+		{a: "10.0.0.1:6443", b: "https://10.0.0.1:6443", want: true},
+		{a: "https://rancher.example.com", b: "https://RANCHER.example.com:443", want: true},
+		{a: "http://rancher.example.com", b: "https://rancher.example.com", want: false},
+		{a: "https://10.0.0.1:6443", b: "https://10.0.0.2:6443", want: false},
+		{a: "", b: "https://10.0.0.1:6443", want: false},
+	}
+	for _, tt := range tests {
+		if got := sameEndpoint(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameEndpoint(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ParseServiceAccountProjects parses a comma separated list of
+// serviceAccount=project pairs, for example
+// "system:serviceaccount:ci:deployer=platform,system:serviceaccount:ml:pipeline=ml"
+func ParseServiceAccountProjects(spec string) (map[string]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		// Service account names contain colons, so split on the last '='
+		i := strings.LastIndex(pair, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid service account mapping %q, expected serviceAccount=project", pair)
+		}
+		serviceAccount := strings.TrimSpace(pair[:i])
+		project := strings.TrimSpace(pair[i+1:])
+		if serviceAccount == "" || project == "" {
+			return nil, fmt.Errorf("invalid service account mapping %q, expected serviceAccount=project", pair)
+		}
+		mapping[serviceAccount] = project
+	}
+	return mapping, nil
+}
+
+// resolveServiceAccount maps the service account recorded in the configured
+// namespace annotation to a project. It returns the service account and a nil
+// reference when the annotation is missing, unmapped or the project is not found.
+func (r *NamespaceReconciler) resolveServiceAccount(ctx context.Context, namespace *corev1.Namespace, clusterID string) (string, *ProjectRef, error) {
+	if r.ServiceAccountAnnotation == "" {
+		return "", nil, nil
+	}
+
+	serviceAccount := strings.TrimSpace(namespace.Annotations[r.ServiceAccountAnnotation])
+	projectName, ok := r.ServiceAccountProjects[serviceAccount]
+	if serviceAccount == "" || !ok {
+		return serviceAccount, nil, nil
+	}
+
+	project, err := r.findProjectByName(ctx, projectName, clusterID)
+	if err != nil || project == nil {
+		return serviceAccount, nil, err
+	}
+	ref := r.projectRef(project, projectName)
+	ref.Source = SourceServiceAccount
+	return serviceAccount, ref, nil
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestParseServiceAccountProjects(t *testing.T) {
+	got, err := ParseServiceAccountProjects("system:serviceaccount:ci:deployer=platform, system:serviceaccount:ml:pipeline = ml")
+	if err != nil {
+		t.Fatalf("ParseServiceAccountProjects() error = %v", err)
+	}
+	want := map[string]string{"system:serviceaccount:ci:deployer": "platform", "system:serviceaccount:ml:pipeline": "ml"}
+	if len(got) != len(want) || got["system:serviceaccount:ci:deployer"] != "platform" || got["system:serviceaccount:ml:pipeline"] != "ml" {
+		t.Errorf("ParseServiceAccountProjects() = %v, want %v", got, want)
+	}
+
+	for _, spec := range []string{"system:serviceaccount:ci:deployer", "=platform", "system:serviceaccount:ci:deployer="} {
+		if _, err := ParseServiceAccountProjects(spec); err == nil {
+			t.Errorf("ParseServiceAccountProjects(%q) error = nil, want an error", spec)
+		}
+	}
+}
+
+func TestReconcileResolvesServiceAccountProject(t *testing.T) {
+	const annotation = "example.io/created-by"
+	tests := []struct {
+		name           string
+		serviceAccount string
+		owner          string
+		want           string
+	}{
+		{name: "mapped service account without owner", serviceAccount: "system:serviceaccount:ci:deployer", want: "p-platform"},
+		{name: "mapped service account wins over owner", serviceAccount: "system:serviceaccount:ci:deployer", owner: "payments", want: "p-platform"},
+		{name: "unmapped service account falls back to owner", serviceAccount: "system:serviceaccount:ci:other", owner: "payments", want: "p-payments"},
+		{name: "mapped project missing falls back to owner", serviceAccount: "system:serviceaccount:ml:pipeline", owner: "payments", want: "p-payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			namespace := newNamespace("checkout", nil)
+			if tt.owner != "" {
+				namespace.Labels = map[string]string{appOwnerLabel: tt.owner}
+			}
+			namespace.Annotations = map[string]string{annotation: tt.serviceAccount}
+			r := newTestReconciler(
+				newProject("local", "p-platform", "platform"),
+				newProject("local", "p-payments", "payments"),
+				namespace,
+			)
+			r.ServiceAccountAnnotation = annotation
+			r.ServiceAccountProjects = map[string]string{
+				"system:serviceaccount:ci:deployer": "platform",
+				"system:serviceaccount:ml:pipeline": "ml",
+			}
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != tt.want {
+				t.Errorf("project label = %q, want %q", got.Labels[rancherProjectIDLabel], tt.want)
+			}
+		})
+	}
+}
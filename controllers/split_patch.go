@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// coreAssignment returns original with only the project ID label and
+// annotation of desired applied, the part of an assignment that must not be
+// blocked by admission rejecting a supplementary key
+func coreAssignment(original, desired *corev1.Namespace) *corev1.Namespace {
+	core := original.DeepCopy()
+	if projectID, ok := desired.Labels[rancherProjectIDLabel]; ok {
+		if core.Labels == nil {
+			core.Labels = make(map[string]string)
+		}
+		core.Labels[rancherProjectIDLabel] = projectID
+	}
+	if projectID, ok := desired.Annotations[rancherProjectIDAnnotation]; ok {
+		if core.Annotations == nil {
+			core.Annotations = make(map[string]string)
+		}
+		core.Annotations[rancherProjectIDAnnotation] = projectID
+	}
+	delete(core.Annotations, pendingProjectAnnotation)
+	return core
+}
+
+// applySupplementary patches the remaining labels and annotations of desired
+// onto the assigned namespace. Failures are reported but do not fail the
+// assignment, which is already in place.
+func (r *NamespaceReconciler) applySupplementary(ctx context.Context, namespaceClient client.Client, namespace, desired *corev1.Namespace, clusterID string) {
+	patch := client.MergeFrom(namespace.DeepCopy())
+	changed := false
+	for key, value := range desired.Labels {
+		if current, ok := namespace.Labels[key]; !ok || current != value {
+			if namespace.Labels == nil {
+				namespace.Labels = make(map[string]string)
+			}
+			namespace.Labels[key] = value
+			changed = true
+		}
+	}
+	for key, value := range desired.Annotations {
+		if current, ok := namespace.Annotations[key]; !ok || current != value {
+			if namespace.Annotations == nil {
+				namespace.Annotations = make(map[string]string)
+			}
+			namespace.Annotations[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := r.patchNamespace(ctx, namespaceClient, clusterID, namespace, patch); err != nil {
+		log.FromContext(ctx).Error(err, "unable to apply supplementary labels and annotations", "namespace", namespace.Name, "clusterId", clusterID)
+		r.eventf(namespace, corev1.EventTypeWarning, "SupplementaryPatchFailed",
+			"Project assigned, but supplementary labels and annotations were rejected: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileSplitPatchesKeepsCoreAssignment(t *testing.T) {
+	tests := []struct {
+		name        string
+		split       bool
+		wantErr     bool
+		wantProject string
+		wantEvent   bool
+	}{
+		{name: "single patch is rejected", wantErr: true},
+		{name: "split patches", split: true, wantProject: "p-payments", wantEvent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler()
+			r.SplitPatches = tt.split
+			r.AnnotateResolutionSource = true
+			recorder := record.NewFakeRecorder(10)
+			r.Recorder = recorder
+
+			// Admission rejects the resolution source annotation but accepts the project ID
+			r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+				WithObjects(newProject("local", "p-payments", "payments"), newNamespace("checkout", map[string]string{appOwnerLabel: "payments"})).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						data, err := patch.Data(obj)
+						if err != nil {
+							return err
+						}
+						if strings.Contains(string(data), resolutionSourceAnnotation) {
+							return apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, obj.GetName(),
+								errors.New("annotation "+resolutionSourceAnnotation+" is not allowed"))
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).Build()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "checkout"}, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+			if got.Labels[rancherProjectIDLabel] != tt.wantProject {
+				t.Errorf("project label = %q, want %q", got.Labels[rancherProjectIDLabel], tt.wantProject)
+			}
+			if _, ok := got.Annotations[resolutionSourceAnnotation]; ok {
+				t.Error("rejected annotation was applied")
+			}
+			var failures int
+			for len(recorder.Events) > 0 {
+				if strings.HasPrefix(<-recorder.Events, corev1.EventTypeWarning+" SupplementaryPatchFailed") {
+					failures++
+				}
+			}
+			if (failures == 1) != tt.wantEvent || failures > 1 {
+				t.Errorf("SupplementaryPatchFailed events = %d, want event %v", failures, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestCoreAssignment(t *testing.T) {
+	original := newNamespace("checkout", map[string]string{appOwnerLabel: "payments"})
+	original.Annotations = map[string]string{pendingProjectAnnotation: "p-payments"}
+	desired := original.DeepCopy()
+	desired.Labels[rancherProjectIDLabel] = "p-payments"
+	desired.Labels["example.io/tier"] = "gold"
+	desired.Annotations[rancherProjectIDAnnotation] = "local:p-payments"
+	desired.Annotations[resolutionSourceAnnotation] = string(SourceLabel)
+
+	core := coreAssignment(original, desired)
+
+	if core.Labels[rancherProjectIDLabel] != "p-payments" || core.Annotations[rancherProjectIDAnnotation] != "local:p-payments" {
+		t.Errorf("core assignment = %v / %v, want the project ID label and annotation", core.Labels, core.Annotations)
+	}
+	if _, ok := core.Labels["example.io/tier"]; ok {
+		t.Error("core assignment carries a supplementary label")
+	}
+	if _, ok := core.Annotations[resolutionSourceAnnotation]; ok {
+		t.Error("core assignment carries a supplementary annotation")
+	}
+	if _, ok := core.Annotations[pendingProjectAnnotation]; ok {
+		t.Error("core assignment keeps the pending project annotation")
+	}
+	if _, ok := original.Labels[rancherProjectIDLabel]; ok {
+		t.Error("coreAssignment modified the original namespace")
+	}
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// staleCacheRequeueDelay is how long to wait for the cache to observe our own patch
+	staleCacheRequeueDelay = 2 * time.Second
+	// staleCacheExpiry bounds how long a patched resourceVersion is remembered
+	staleCacheExpiry = time.Minute
+)
+
+// patchedVersions remembers the resourceVersion each namespace had when it was
+// last patched. Reading that version again means the cache has not caught up
+// with the patch yet, so re-patching would only repeat the same change.
+type patchedVersions struct {
+	mutex    sync.Mutex
+	versions map[string]patchedVersion
+}
+
+type patchedVersion struct {
+	resourceVersion string
+	patched         time.Time
+}
+
+// record stores the resourceVersion a namespace was read at before patching
+func (p *patchedVersions) record(clusterID, namespace, resourceVersion string, now time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.versions == nil {
+		p.versions = make(map[string]patchedVersion)
+	}
+	p.versions[clusterID+"/"+namespace] = patchedVersion{resourceVersion: resourceVersion, patched: now}
+}
+
+// stale reports whether the namespace was read at the version it had before
+// our last patch. Entries are dropped once the cache moves past them or expire.
+func (p *patchedVersions) stale(clusterID, namespace, resourceVersion string, now time.Time) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	key := clusterID + "/" + namespace
+	entry, ok := p.versions[key]
+	if !ok {
+		return false
+	}
+	if entry.resourceVersion == resourceVersion && now.Sub(entry.patched) < staleCacheExpiry {
+		return true
+	}
+	delete(p.versions, key)
+	return false
+}
+
+// forget drops the entry for a namespace, for example after it was deleted
+func (p *patchedVersions) forget(clusterID, namespace string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.versions, clusterID+"/"+namespace)
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileWaitsForStaleCacheAfterPatch(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+
+	// The cache keeps serving the pre-patch namespace until it catches up
+	var stale *corev1.Namespace
+	cacheBehind := false
+	patches := 0
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(newProject("local", "p-live", "payments"), newNamespace("payments", map[string]string{appOwnerLabel: "payments"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if namespace, ok := obj.(*corev1.Namespace); ok && cacheBehind && stale != nil {
+					stale.DeepCopyInto(namespace)
+					return nil
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if namespace, ok := obj.(*corev1.Namespace); ok && stale == nil {
+					current := &corev1.Namespace{}
+					if err := c.Get(ctx, client.ObjectKeyFromObject(namespace), current); err != nil {
+						return err
+					}
+					stale = current
+				}
+				patches++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("first Reconcile() error = %v", err)
+	}
+	assignPatches := patches
+
+	// The stale read short-circuits with a brief requeue instead of re-patching
+	cacheBehind = true
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("stale Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != staleCacheRequeueDelay {
+		t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, staleCacheRequeueDelay)
+	}
+	if patches != assignPatches {
+		t.Errorf("stale read patched the namespace %d more times", patches-assignPatches)
+	}
+
+	// Once the cache has caught up the namespace is recognized as assigned
+	cacheBehind = false
+	result, err = r.Reconcile(ctx, req)
+	if err != nil || result != (ctrl.Result{}) {
+		t.Errorf("Reconcile() after catch-up = (%+v, %v), want no requeue", result, err)
+	}
+	if patches != assignPatches {
+		t.Errorf("reconcile after catch-up patched the namespace %d more times", patches-assignPatches)
+	}
+}
+
+func TestPatchedVersionsStaleAndForget(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		clusterID string
+		forget    bool
+		version   string
+		after     time.Duration
+		want      bool
+	}{
+		{name: "same version right after the patch", version: "41", want: true},
+		{name: "cache moved past the patch", version: "42", want: false},
+		{name: "entry expired", version: "41", after: staleCacheExpiry, want: false},
+		{name: "forgotten after deletion", forget: true, version: "41", want: false},
+		{name: "other cluster", clusterID: "c-def", version: "41", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var versions patchedVersions
+			versions.record("c-abc", "payments", "41", start)
+			if tt.forget {
+				versions.forget("c-abc", "payments")
+			}
+			clusterID := "c-abc"
+			if tt.clusterID != "" {
+				clusterID = tt.clusterID
+			}
+			if got := versions.stale(clusterID, "payments", tt.version, start.Add(tt.after)); got != tt.want {
+				t.Errorf("stale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A read past the patched version drops the entry for good
+	var versions patchedVersions
+	versions.record("c-abc", "payments", "41", start)
+	versions.stale("c-abc", "payments", "42", start)
+	if versions.stale("c-abc", "payments", "41", start) {
+		t.Error("entry still reported stale after the cache moved past it")
+	}
+}
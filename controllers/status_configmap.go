@@ -0,0 +1,331 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// statusFlushInterval is how often queued decisions are written to the
+	// status ConfigMap, so workers never wait on the shared object
+	statusFlushInterval = 10 * time.Second
+	// statusConfigMapMaxBytes keeps the status ConfigMap below the 1 MiB
+	// object limit; the oldest entries are dropped beyond it
+	statusConfigMapMaxBytes = 900 * 1024
+
+	// Condition types of a namespace status entry
+	ConditionAssigned   = "Assigned"
+	ConditionReconciled = "Reconciled"
+)
+
+// namespaceStatus is the per-namespace entry stored in the status ConfigMap
+// under the key <clusterId>.<namespace> of the cluster the namespace lives
+// on. ClusterID is the cluster of the assigned project.
+type namespaceStatus struct {
+	// Time is when the namespace was last reconciled
+	Time       time.Time          `json:"time"`
+	Namespace  string             `json:"namespace"`
+	ClusterID  string             `json:"clusterId,omitempty"`
+	ProjectID  string             `json:"projectId,omitempty"`
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// statusKey is the ConfigMap key of a namespace. Namespace names cannot
+// contain dots, so keys of different clusters never collide.
+func statusKey(clusterID, namespace string) string {
+	return clusterID + "." + namespace
+}
+
+// terminalStatusReason reports whether the reason describes where a namespace
+// ended up. Reconciles that are only postponed do not change its status.
+func terminalStatusReason(reason ReconcileReason) bool {
+	switch reason {
+	case ReasonThrottled, ReasonDeferred, ReasonPlanned, ReasonStaleCache, ReasonClusterUpgrading:
+		return false
+	default:
+		return true
+	}
+}
+
+// statusUpdates collects decisions between status ConfigMap flushes, keeping
+// the latest decision per namespace
+type statusUpdates struct {
+	mutex   sync.Mutex
+	pending map[string]Decision
+	trace   []Decision
+}
+
+// queueStatus queues the decision for the next status ConfigMap flush
+func (r *NamespaceReconciler) queueStatus(decision Decision) {
+	if r.StatusConfigMap == "" || !terminalStatusReason(decision.Reason) {
+		return
+	}
+
+	s := &r.statusUpdates
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pending == nil {
+		s.pending = make(map[string]Decision)
+	}
+	s.pending[statusKey(decision.namespaceKey())] = decision
+	if r.DecisionTraceLimit > 0 {
+		s.trace = append(s.trace, decision)
+		if len(s.trace) > r.DecisionTraceLimit {
+			s.trace = s.trace[len(s.trace)-r.DecisionTraceLimit:]
+		}
+	}
+}
+
+// take returns and clears the queued decisions
+func (s *statusUpdates) take() (map[string]Decision, []Decision) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pending, trace := s.pending, s.trace
+	s.pending, s.trace = nil, nil
+	return pending, trace
+}
+
+// restore requeues decisions of a failed flush. Decisions queued since the
+// flush began are newer and win; the restored trace goes before them.
+func (s *statusUpdates) restore(pending map[string]Decision, trace []Decision, traceLimit int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pending == nil {
+		s.pending = make(map[string]Decision, len(pending))
+	}
+	for key, decision := range pending {
+		if _, ok := s.pending[key]; !ok {
+			s.pending[key] = decision
+		}
+	}
+	if len(trace) > 0 {
+		s.trace = append(trace, s.trace...)
+		if traceLimit > 0 && len(s.trace) > traceLimit {
+			s.trace = s.trace[len(s.trace)-traceLimit:]
+		}
+	}
+}
+
+// runStatusFlusher writes queued decisions to the status ConfigMap every
+// statusFlushInterval until the context is cancelled, then flushes once more
+func (r *NamespaceReconciler) runStatusFlusher(ctx context.Context) error {
+	ticker := r.clock().NewTicker(statusFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// The manager is stopping, so flush with a context of our own
+			flushCtx, cancel := context.WithTimeout(context.Background(), statusFlushInterval)
+			defer cancel()
+			r.flushStatusConfigMap(log.IntoContext(flushCtx, log.FromContext(ctx)))
+			return nil
+		case <-ticker.C():
+			r.flushStatusConfigMap(ctx)
+		}
+	}
+}
+
+// flushStatusConfigMap merges the queued decisions into the status ConfigMap
+// in a single update, removing entries of deleted namespaces and appending to
+// the decision trace. Updates use the ConfigMap resourceVersion and are
+// retried on conflict. A failed flush requeues its decisions for the next one.
+func (r *NamespaceReconciler) flushStatusConfigMap(ctx context.Context) {
+	pending, trace := r.statusUpdates.take()
+	if len(pending) == 0 && len(trace) == 0 {
+		return
+	}
+	logger := log.FromContext(ctx)
+
+	// Read the ConfigMap directly so the manager does not start a ConfigMap informer
+	var reader client.Reader = r.Client
+	if r.Manager != nil {
+		reader = r.Manager.GetAPIReader()
+	}
+
+	key := types.NamespacedName{Namespace: r.StatusNamespace, Name: r.StatusConfigMap}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{}
+		if err := reader.Get(ctx, key, configMap); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		}
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+
+		for entryKey, decision := range pending {
+			if err := applyStatusDecision(configMap.Data, entryKey, decision); err != nil {
+				return err
+			}
+		}
+		if dropped := pruneStatusEntries(configMap.Data, statusConfigMapMaxBytes); dropped > 0 {
+			logger.Info("status ConfigMap is full, dropped the oldest entries", "configMap", key.String(), "dropped", dropped)
+		}
+
+		// Keep the last decisions across all namespaces for quick triage
+		if len(trace) > 0 {
+			value, err := appendDecisionTrace(configMap.Annotations[decisionTraceAnnotation], trace, r.DecisionTraceLimit)
+			if err != nil {
+				return err
+			}
+			if configMap.Annotations == nil {
+				configMap.Annotations = make(map[string]string)
+			}
+			configMap.Annotations[decisionTraceAnnotation] = value
+		}
+
+		if configMap.ResourceVersion == "" {
+			return r.Create(ctx, configMap)
+		}
+		return r.Update(ctx, configMap)
+	})
+	if err != nil {
+		logger.Error(err, "unable to update status ConfigMap", "configMap", key.String(), "namespaces", len(pending))
+		r.statusUpdates.restore(pending, trace, r.DecisionTraceLimit)
+	}
+}
+
+// applyStatusDecision updates the namespace entry with the decision, or
+// removes it when the namespace no longer exists
+func applyStatusDecision(data map[string]string, key string, decision Decision) error {
+	if decision.Reason == ReasonNamespaceNotFound {
+		delete(data, key)
+		return nil
+	}
+
+	status := namespaceStatus{}
+	if value, ok := data[key]; ok {
+		// A corrupted entry is replaced rather than blocking status updates
+		_ = json.Unmarshal([]byte(value), &status)
+	}
+	status.Time = decision.Time
+	status.Namespace = decision.Namespace
+	status.ClusterID = decision.ClusterID
+	status.ProjectID = decision.ProjectID
+
+	assigned := metav1.Condition{Type: ConditionAssigned, Status: metav1.ConditionFalse, Reason: string(decision.Reason),
+		Message: decision.Message, LastTransitionTime: metav1.NewTime(decision.Time)}
+	if decision.Reason == ReasonAssigned || decision.Reason == ReasonAlreadyAssigned || decision.Reason == ReasonClusterIDBackfilled {
+		assigned.Status = metav1.ConditionTrue
+	}
+	reconciled := metav1.Condition{Type: ConditionReconciled, Status: metav1.ConditionTrue, Reason: decision.Outcome,
+		Message: decision.Message, LastTransitionTime: metav1.NewTime(decision.Time)}
+	if decision.Outcome == DecisionError {
+		reconciled.Status = metav1.ConditionFalse
+	}
+	// Errors say nothing about the assignment, so it keeps its last state
+	if decision.Outcome != DecisionError {
+		meta.SetStatusCondition(&status.Conditions, assigned)
+	}
+	meta.SetStatusCondition(&status.Conditions, reconciled)
+
+	entry, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	data[key] = string(entry)
+	return nil
+}
+
+// pruneStatusEntries drops the entries reconciled longest ago until the data
+// fits in maxBytes and returns how many were dropped
+func pruneStatusEntries(data map[string]string, maxBytes int) int {
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+	}
+	if size <= maxBytes {
+		return 0
+	}
+
+	type entryAge struct {
+		key  string
+		last time.Time
+	}
+	entries := make([]entryAge, 0, len(data))
+	for key, value := range data {
+		status := namespaceStatus{}
+		_ = json.Unmarshal([]byte(value), &status)
+		entries = append(entries, entryAge{key: key, last: status.Time})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].last.Before(entries[j].last) })
+
+	dropped := 0
+	for _, entry := range entries {
+		if size <= maxBytes {
+			break
+		}
+		size -= len(entry.key) + len(data[entry.key])
+		delete(data, entry.key)
+		dropped++
+	}
+	return dropped
+}
+
+const (
+	// decisionTraceAnnotation on the status ConfigMap holds the most recent
+	// decisions across all namespaces, oldest first
+	decisionTraceAnnotation = "rancher-operator.quiknode.io/decision-trace"
+	// MaxDecisionTraceLimit bounds the number of decisions in the trace
+	MaxDecisionTraceLimit = 1000
+	// decisionTraceMaxBytes keeps the trace well below the 256 KiB limit on
+	// all annotations of an object
+	decisionTraceMaxBytes = 128 * 1024
+)
+
+// appendDecisionTrace adds the decisions to the JSON encoded trace and drops
+// the oldest entries beyond limit, or beyond decisionTraceMaxBytes
+func appendDecisionTrace(value string, decisions []Decision, limit int) (string, error) {
+	var trace []json.RawMessage
+	if value != "" {
+		// A corrupted trace is replaced rather than blocking status updates
+		if err := json.Unmarshal([]byte(value), &trace); err != nil {
+			trace = nil
+		}
+	}
+
+	for _, decision := range decisions {
+		entry, err := json.Marshal(decision)
+		if err != nil {
+			return "", err
+		}
+		trace = append(trace, entry)
+	}
+	limit = min(limit, MaxDecisionTraceLimit)
+	if len(trace) > limit {
+		trace = trace[len(trace)-limit:]
+	}
+
+	// Keep the newest entries that fit, counting brackets and separators
+	size := 2
+	first := len(trace)
+	for first > 0 && size+len(trace[first-1])+1 <= decisionTraceMaxBytes {
+		first--
+		size += len(trace[first]) + 1
+	}
+	trace = trace[first:]
+
+	data, err := json.Marshal(trace)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func readStatusConfigMap(t *testing.T, r *NamespaceReconciler) *corev1.ConfigMap {
+	t.Helper()
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: r.StatusNamespace, Name: r.StatusConfigMap}, configMap); err != nil {
+		t.Fatalf("Get(status ConfigMap) error = %v", err)
+	}
+	return configMap
+}
+
+func TestStatusConfigMapEntries(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	r.StatusConfigMap, r.StatusNamespace = "status", "operator"
+	now := time.Now()
+
+	// Added for two namespaces of the same name on different clusters
+	r.queueStatus(Decision{Time: now, Namespace: "payments", ClusterID: "local", Outcome: DecisionSkipped, Reason: ReasonProjectNotFound})
+	r.queueStatus(Decision{Time: now, Namespace: "payments", ClusterID: "c-abc", Outcome: DecisionAssigned, Reason: ReasonAssigned, ProjectID: "p-live"})
+	r.flushStatusConfigMap(ctx)
+
+	configMap := readStatusConfigMap(t, r)
+	if len(configMap.Data) != 2 {
+		t.Fatalf("status ConfigMap has %d entries, want 2: %v", len(configMap.Data), configMap.Data)
+	}
+	status := namespaceStatus{}
+	if err := json.Unmarshal([]byte(configMap.Data["c-abc.payments"]), &status); err != nil {
+		t.Fatalf("unable to decode entry: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(status.Conditions, ConditionAssigned) || status.ProjectID != "p-live" {
+		t.Errorf("entry c-abc.payments = %+v, want Assigned to p-live", status)
+	}
+
+	// Updated, while postponed reconciles leave the entry alone
+	r.queueStatus(Decision{Time: now, Namespace: "payments", ClusterID: "local", Outcome: DecisionAssigned, Reason: ReasonAssigned, ProjectID: "p-local"})
+	r.queueStatus(Decision{Time: now, Namespace: "payments", ClusterID: "c-abc", Outcome: DecisionSkipped, Reason: ReasonThrottled})
+	r.flushStatusConfigMap(ctx)
+
+	configMap = readStatusConfigMap(t, r)
+	status = namespaceStatus{}
+	_ = json.Unmarshal([]byte(configMap.Data["local.payments"]), &status)
+	if condition := meta.FindStatusCondition(status.Conditions, ConditionAssigned); condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("entry local.payments = %+v, want Assigned", status)
+	}
+	status = namespaceStatus{}
+	_ = json.Unmarshal([]byte(configMap.Data["c-abc.payments"]), &status)
+	if status.ProjectID != "p-live" {
+		t.Errorf("throttled reconcile changed entry c-abc.payments to %+v", status)
+	}
+
+	// Removed once the namespace is deleted
+	r.queueStatus(Decision{Time: now, Namespace: "payments", ClusterID: "local", Reason: ReasonNamespaceNotFound})
+	r.flushStatusConfigMap(ctx)
+
+	configMap = readStatusConfigMap(t, r)
+	if _, ok := configMap.Data["local.payments"]; ok {
+		t.Errorf("entry of deleted namespace was not removed: %v", configMap.Data)
+	}
+	if _, ok := configMap.Data["c-abc.payments"]; !ok {
+		t.Errorf("entry of the other cluster was removed: %v", configMap.Data)
+	}
+}
+
+func TestPruneStatusEntriesDropsOldest(t *testing.T) {
+	data := map[string]string{}
+	for i, name := range []string{"old", "middle", "new"} {
+		entry, _ := json.Marshal(namespaceStatus{Time: time.Unix(int64(i), 0), Namespace: name})
+		data["local."+name] = string(entry)
+	}
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+	}
+
+	if dropped := pruneStatusEntries(data, size-1); dropped != 1 {
+		t.Fatalf("pruneStatusEntries() dropped %d entries, want 1", dropped)
+	}
+	if _, ok := data["local.old"]; ok {
+		t.Errorf("pruneStatusEntries() kept the oldest entry: %v", data)
+	}
+}
+
+func TestAppendDecisionTraceCapsEntries(t *testing.T) {
+	value := ""
+	for i := 0; i < 5; i++ {
+		var err error
+		value, err = appendDecisionTrace(value, []Decision{{Namespace: fmt.Sprintf("ns-%d", i)}}, 3)
+		if err != nil {
+			t.Fatalf("appendDecisionTrace() error = %v", err)
+		}
+	}
+
+	var trace []Decision
+	if err := json.Unmarshal([]byte(value), &trace); err != nil {
+		t.Fatalf("unable to decode trace: %v", err)
+	}
+	if len(trace) != 3 || trace[0].Namespace != "ns-2" || trace[2].Namespace != "ns-4" {
+		t.Errorf("trace = %+v, want ns-2 to ns-4", trace)
+	}
+}
+
+func TestAppendDecisionTraceCapsBytes(t *testing.T) {
+	message := strings.Repeat("x", 1024)
+	decisions := make([]Decision, MaxDecisionTraceLimit)
+	for i := range decisions {
+		decisions[i] = Decision{Namespace: fmt.Sprintf("ns-%d", i), Message: message}
+	}
+
+	value, err := appendDecisionTrace("", decisions, MaxDecisionTraceLimit)
+	if err != nil {
+		t.Fatalf("appendDecisionTrace() error = %v", err)
+	}
+	if len(value) > decisionTraceMaxBytes {
+		t.Errorf("trace is %d bytes, want at most %d", len(value), decisionTraceMaxBytes)
+	}
+
+	var trace []Decision
+	if err := json.Unmarshal([]byte(value), &trace); err != nil {
+		t.Fatalf("unable to decode trace: %v", err)
+	}
+	if last := trace[len(trace)-1].Namespace; last != fmt.Sprintf("ns-%d", MaxDecisionTraceLimit-1) {
+		t.Errorf("newest trace entry = %s, want the last decision", last)
+	}
+}
+
+func TestStatusConfigMapFlushRetriesFailedBatch(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	failing := true
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if failing {
+				return fmt.Errorf("apiserver unavailable")
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	r.StatusConfigMap, r.StatusNamespace = "status", "operator"
+	now := time.Now()
+
+	r.queueStatus(Decision{Time: now, Namespace: "payments", ClusterID: "local", Outcome: DecisionAssigned, Reason: ReasonAssigned, ProjectID: "p-live"})
+	r.queueStatus(Decision{Time: now, Namespace: "orders", ClusterID: "local", Outcome: DecisionSkipped, Reason: ReasonProjectNotFound})
+	r.flushStatusConfigMap(ctx)
+
+	// A decision queued after the failed flush is newer than the restored one
+	r.queueStatus(Decision{Time: now.Add(time.Second), Namespace: "orders", ClusterID: "local", Outcome: DecisionAssigned, Reason: ReasonAssigned, ProjectID: "p-orders"})
+	failing = false
+	r.flushStatusConfigMap(ctx)
+
+	configMap := readStatusConfigMap(t, r)
+	for key, projectID := range map[string]string{"local.payments": "p-live", "local.orders": "p-orders"} {
+		status := namespaceStatus{}
+		if err := json.Unmarshal([]byte(configMap.Data[key]), &status); err != nil {
+			t.Fatalf("entry %s missing after retried flush: %v", key, err)
+		}
+		if status.ProjectID != projectID {
+			t.Errorf("entry %s projectId = %q, want %q", key, status.ProjectID, projectID)
+		}
+	}
+}
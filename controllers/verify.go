@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reasons an assignment is reported as dangling
+const (
+	DanglingProjectMissing     = "ProjectMissing"
+	DanglingProjectTerminating = "ProjectTerminating"
+	DanglingProjectIneligible  = "ProjectIneligible"
+)
+
+// DanglingAssignment is a namespace whose projectId references a project that
+// no longer exists or is no longer eligible. Only namespaces whose owner the
+// operator resolves are verified, and protected projects such as System are
+// never reported.
+type DanglingAssignment struct {
+	Namespace string `json:"namespace"`
+	ProjectID string `json:"projectId"`
+	Reason    string `json:"reason"`
+	Fixed     bool   `json:"fixed"`
+}
+
+// AssignmentVerifier periodically confirms that every assigned namespace still
+// references an existing, eligible project. With Fix enabled the dangling
+// projectId is cleared so the controller can resolve the namespace again.
+type AssignmentVerifier struct {
+	Reconciler *NamespaceReconciler
+	// Interval between verification passes
+	Interval time.Duration
+	// Fix clears the projectId label and annotation of dangling assignments
+	Fix bool
+}
+
+// Start runs a verification pass every interval until the context is cancelled. It implements manager.Runnable.
+func (v *AssignmentVerifier) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("verify")
+	ctx = log.IntoContext(ctx, logger)
+
+	ticker := v.Reconciler.clock().NewTicker(v.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			dangling, err := v.Verify(ctx)
+			if err != nil {
+				logger.Error(err, "assignment verification failed")
+				continue
+			}
+			logger.Info("assignment verification finished", "dangling", len(dangling), "report", dangling)
+		}
+	}
+}
+
+// Verify checks all assigned namespaces once and returns the dangling ones
+func (v *AssignmentVerifier) Verify(ctx context.Context) ([]DanglingAssignment, error) {
+	r := v.Reconciler
+	logger := log.FromContext(ctx)
+
+	clusterID, namespaceClient := r.getClusterClient(ctx, ctrl.Request{})
+	if namespaceClient == nil {
+		return nil, fmt.Errorf("no client available for cluster %s", clusterID)
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := namespaceClient.List(ctx, namespaces); err != nil {
+		return nil, clusterError(clusterID, "unable to list namespaces", err)
+	}
+
+	projects, truncated, err := r.listProjects(ctx, "")
+	if err != nil {
+		return nil, clusterError(clusterID, "unable to list projects", err)
+	}
+	// Project names are only unique within their cluster namespace
+	byKey := make(map[string]*unstructured.Unstructured, len(projects))
+	byName := make(map[string]*unstructured.Unstructured, len(projects))
+	for i := range projects {
+		byKey[projects[i].GetNamespace()+"/"+projects[i].GetName()] = &projects[i]
+		byName[projects[i].GetName()] = &projects[i]
+	}
+
+	var dangling []DanglingAssignment
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		projectID := namespace.Labels[rancherProjectIDLabel]
+		if projectID == "" {
+			continue
+		}
+
+		// Namespaces the operator does not resolve, such as kube-system, were assigned by someone else
+		res, err := r.resolveNamespace(ctx, namespaceClient, namespace, clusterID)
+		if err != nil || res.Skip != "" {
+			logger.V(1).Info("namespace owner not resolved, skipping verification", "namespace", namespace.Name, "projectId", projectID, "error", err)
+			continue
+		}
+
+		var project *unstructured.Unstructured
+		var found bool
+		projectClusterID, projectName := r.assignedProject(namespace)
+		if projectClusterID != "" {
+			project, found = byKey[projectClusterID+"/"+projectName]
+		} else {
+			project, found = byName[projectName]
+		}
+
+		var reason string
+		switch {
+		case found && r.isProtectedProject(&ProjectRef{Project: project}, ""):
+			continue
+		case !found && truncated:
+			// The project may be beyond the List cap, so it cannot be judged
+			logger.V(1).Info("project not in capped list, skipping verification", "namespace", namespace.Name, "projectId", projectID)
+			continue
+		case !found:
+			reason = DanglingProjectMissing
+		case projectTerminating(project):
+			reason = DanglingProjectTerminating
+		case !r.projectEligible(project):
+			reason = DanglingProjectIneligible
+		default:
+			continue
+		}
+
+		assignment := DanglingAssignment{Namespace: namespace.Name, ProjectID: projectID, Reason: reason}
+		if v.Fix {
+			if err := r.clearDanglingAssignment(ctx, namespaceClient, clusterID, namespace.Name, projectID); err != nil {
+				logger.Error(err, "unable to clear dangling assignment", "namespace", namespace.Name, "projectId", projectID)
+			} else {
+				assignment.Fixed = true
+			}
+		}
+		dangling = append(dangling, assignment)
+	}
+
+	return dangling, nil
+}
+
+// assignedProject splits the namespace's projectId into the project's cluster
+// and name. The label holds either "p-xxx" or "c-xxx:p-xxx"; the cluster of a
+// bare name comes from Rancher's annotation or the clusterId label, and is
+// empty when neither is set.
+func (r *NamespaceReconciler) assignedProject(namespace *corev1.Namespace) (string, string) {
+	projectID := namespace.Labels[rancherProjectIDLabel]
+	projectName := projectID[strings.LastIndex(projectID, ":")+1:]
+
+	clusterID := r.extractClusterID(projectID)
+	if clusterID == "" {
+		clusterID = r.extractClusterID(namespace.Annotations[rancherProjectIDAnnotation])
+	}
+	if clusterID == "" {
+		clusterID = namespace.Labels[rancherClusterIDLabel]
+	}
+	// Projects of the management cluster live in the "local" namespace
+	if clusterID != "" && clusterID == r.LocalClusterID {
+		clusterID = "local"
+	}
+	return clusterID, projectName
+}
+
+// clearDanglingAssignment clears the namespace's assignment under the
+// namespace lock, unless a reconcile changed it since the namespace was listed
+func (r *NamespaceReconciler) clearDanglingAssignment(ctx context.Context, namespaceClient client.Client, clusterID, name, projectID string) error {
+	unlock := r.namespaceLocks.lock(clusterID + "/" + name)
+	defer unlock()
+
+	namespace := &corev1.Namespace{}
+	if err := namespaceClient.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		return err
+	}
+	if namespace.Labels[rancherProjectIDLabel] != projectID {
+		return fmt.Errorf("assignment of namespace %s changed to %q while verifying", name, namespace.Labels[rancherProjectIDLabel])
+	}
+	return clearAssignment(ctx, namespaceClient, namespace)
+}
+
+// clearAssignment removes the Rancher projectId label and annotation
+func clearAssignment(ctx context.Context, namespaceClient client.Client, namespace *corev1.Namespace) error {
+	patch := client.MergeFrom(namespace.DeepCopy())
+	delete(namespace.Labels, rancherProjectIDLabel)
+	delete(namespace.Annotations, rancherProjectIDAnnotation)
+	return namespaceClient.Patch(ctx, namespace, patch)
+}
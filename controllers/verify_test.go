@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestVerifyReportsDanglingAssignments(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("c-abc", "p-live", "payments"),
+		newProject("c-abc", "p-system", "System"),
+		// Assigned to an existing project, in both projectId forms
+		newNamespace("payments", map[string]string{appOwnerLabel: "payments", rancherProjectIDLabel: "p-live", rancherClusterIDLabel: "c-abc"}),
+		newNamespace("payments-qualified", map[string]string{appOwnerLabel: "payments", rancherProjectIDLabel: "c-abc:p-live"}),
+		// Assigned to a project that was deleted
+		newNamespace("orders", map[string]string{appOwnerLabel: "orders", rancherProjectIDLabel: "p-gone", rancherClusterIDLabel: "c-abc"}),
+		// Not owned by the operator, assigned by Rancher
+		newNamespace("kube-system", map[string]string{rancherProjectIDLabel: "p-gone", rancherClusterIDLabel: "c-abc"}),
+		// Owned, but assigned to a protected project
+		newNamespace("agents", map[string]string{appOwnerLabel: "agents", rancherProjectIDLabel: "c-abc:p-system"}),
+	)
+
+	verifier := &AssignmentVerifier{Reconciler: r}
+	dangling, err := verifier.Verify(ctx)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(dangling) != 1 {
+		t.Fatalf("Verify() reported %d dangling assignments, want 1: %+v", len(dangling), dangling)
+	}
+	want := DanglingAssignment{Namespace: "orders", ProjectID: "p-gone", Reason: DanglingProjectMissing}
+	if dangling[0] != want {
+		t.Errorf("Verify() = %+v, want %+v", dangling[0], want)
+	}
+}
+
+func TestVerifyFixClearsOnlyDanglingAssignments(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(
+		newProject("c-abc", "p-live", "payments"),
+		newNamespace("payments", map[string]string{appOwnerLabel: "payments", rancherProjectIDLabel: "c-abc:p-live"}),
+		newNamespace("orders", map[string]string{appOwnerLabel: "orders", rancherProjectIDLabel: "c-abc:p-gone"}),
+		newNamespace("kube-system", map[string]string{rancherProjectIDLabel: "c-abc:p-gone"}),
+	)
+
+	verifier := &AssignmentVerifier{Reconciler: r, Fix: true}
+	dangling, err := verifier.Verify(ctx)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(dangling) != 1 || !dangling[0].Fixed {
+		t.Fatalf("Verify() = %+v, want one fixed assignment", dangling)
+	}
+
+	for name, wantProjectID := range map[string]string{
+		"payments":    "c-abc:p-live",
+		"orders":      "",
+		"kube-system": "c-abc:p-gone",
+	} {
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		if got := namespace.Labels[rancherProjectIDLabel]; got != wantProjectID {
+			t.Errorf("namespace %s projectId = %q, want %q", name, got, wantProjectID)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// projectWeightAnnotation sets a project's share of assignments under the
+// weighted ambiguity policy. Projects without it weigh 1; 0 excludes them.
+const projectWeightAnnotation = "rancher-operator.quiknode.io/weight"
+
+// projectWeight returns the configured weight of a project
+func projectWeight(project *unstructured.Unstructured) uint64 {
+	value, ok := project.GetAnnotations()[projectWeightAnnotation]
+	if !ok {
+		return 1
+	}
+	weight, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 1
+	}
+	return weight
+}
+
+// selectWeighted spreads namespaces across the sorted candidates in
+// proportion to their weights. The namespace name is hashed, so the same
+// namespace keeps the same project while the candidates do not change.
+func selectWeighted(candidates []*unstructured.Unstructured, namespace string) *unstructured.Unstructured {
+	var total uint64
+	for _, candidate := range candidates {
+		total += projectWeight(candidate)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(namespace))
+	point := hash.Sum64() % total
+
+	for _, candidate := range candidates {
+		weight := projectWeight(candidate)
+		if point < weight {
+			return candidate
+		}
+		point -= weight
+	}
+	return nil
+}
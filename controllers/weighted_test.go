@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSelectWeightedDistributesByWeight(t *testing.T) {
+	weighted := func(name, weight string) *unstructured.Unstructured {
+		project := newProject("local", name, "payments")
+		if weight != "" {
+			project.SetAnnotations(map[string]string{projectWeightAnnotation: weight})
+		}
+		return project
+	}
+	candidates := []*unstructured.Unstructured{
+		weighted("p-shard-a", ""),
+		weighted("p-shard-b", "3"),
+		weighted("p-shard-c", "0"),
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 2000; i++ {
+		namespace := fmt.Sprintf("payments-%d", i)
+		selected := selectWeighted(candidates, namespace)
+		if selected == nil {
+			t.Fatalf("selectWeighted(%s) = nil", namespace)
+		}
+		// The choice is stable for a namespace
+		if again := selectWeighted(candidates, namespace); again.GetName() != selected.GetName() {
+			t.Fatalf("selectWeighted(%s) changed from %s to %s", namespace, selected.GetName(), again.GetName())
+		}
+		counts[selected.GetName()]++
+	}
+
+	// Weights 1:3:0 give a quarter, three quarters and nothing
+	if counts["p-shard-c"] != 0 {
+		t.Errorf("zero-weight project received %d namespaces", counts["p-shard-c"])
+	}
+	if a := counts["p-shard-a"]; a < 400 || a > 600 {
+		t.Errorf("p-shard-a received %d of 2000 namespaces, want about 500", a)
+	}
+	if b := counts["p-shard-b"]; b < 1400 || b > 1600 {
+		t.Errorf("p-shard-b received %d of 2000 namespaces, want about 1500", b)
+	}
+}
+
+func TestSelectWeightedAllWeightsZero(t *testing.T) {
+	project := newProject("local", "p-shard-a", "payments")
+	project.SetAnnotations(map[string]string{projectWeightAnnotation: "0"})
+	if selected := selectWeighted([]*unstructured.Unstructured{project}, "payments"); selected != nil {
+		t.Errorf("selectWeighted() = %s, want nil when every weight is zero", selected.GetName())
+	}
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"fmt"
+)
+
+// WriteTargets selects which metadata updateNamespaceWithProject writes
+type WriteTargets string
+
+const (
+	// WriteBoth writes project labels and annotations
+	WriteBoth WriteTargets = "both"
+	// WriteLabelsOnly writes only the project and cluster labels
+	WriteLabelsOnly WriteTargets = "labels"
+	// WriteAnnotationsOnly writes only annotations
+	WriteAnnotationsOnly WriteTargets = "annotations"
+)
+
+// ParseWriteTargets validates a write target supplied on the command line
+func ParseWriteTargets(value string) (WriteTargets, error) {
+	switch targets := WriteTargets(value); targets {
+	case WriteBoth, WriteLabelsOnly, WriteAnnotationsOnly:
+		return targets, nil
+	default:
+		return "", fmt.Errorf("unknown write targets %q", value)
+	}
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileWritesOnlySelectedTargets(t *testing.T) {
+	tests := []struct {
+		targets        WriteTargets
+		wantLabel      bool
+		wantAnnotation bool
+	}{
+		{targets: WriteBoth, wantLabel: true, wantAnnotation: true},
+		{targets: WriteLabelsOnly, wantLabel: true},
+		{targets: WriteAnnotationsOnly, wantAnnotation: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.targets), func(t *testing.T) {
+			ctx := context.Background()
+			namespace := newNamespace("payments", map[string]string{appOwnerLabel: "payments"})
+			namespace.Annotations = map[string]string{"example.com/contact": "payments@example.com"}
+			r := newTestReconciler(newProject("local", "p-live", "payments"), namespace)
+			r.WriteTargets = tt.targets
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "payments"}}
+
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &corev1.Namespace{}
+			if err := r.Get(ctx, req.NamespacedName, got); err != nil {
+				t.Fatalf("get namespace: %v", err)
+			}
+
+			if _, ok := got.Labels[rancherProjectIDLabel]; ok != tt.wantLabel {
+				t.Errorf("project label written = %v, want %v", ok, tt.wantLabel)
+			}
+			if _, ok := got.Labels[rancherClusterIDLabel]; ok != tt.wantLabel {
+				t.Errorf("cluster label written = %v, want %v", ok, tt.wantLabel)
+			}
+			if _, ok := got.Annotations[rancherProjectIDAnnotation]; ok != tt.wantAnnotation {
+				t.Errorf("project annotation written = %v, want %v", ok, tt.wantAnnotation)
+			}
+			// The target that is not selected keeps only the metadata the operator does not own
+			if !tt.wantLabel && len(got.Labels) != 1 {
+				t.Errorf("labels = %v, want only the appOwner label", got.Labels)
+			}
+			if !tt.wantAnnotation && len(got.Annotations) != 1 {
+				t.Errorf("annotations = %v, want only the contact annotation", got.Annotations)
+			}
+		})
+	}
+}
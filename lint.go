@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/quiknode-labs/qn-rancher-operator/controllers"
+)
+
+// runLint implements the lint subcommand. It reports namespaces whose owner
+// labels do not conform to a pattern or do not resolve to a project, without
+// making any changes, and returns the process exit code.
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	ownerPattern := fs.String("owner-pattern", "", "Regular expression every appOwner label must match.")
+	output := fs.String("output", "table", "Report format: table or json.")
+	defaultBehavior := fs.String("default-behavior", string(controllers.BehaviorV1), "Resolution behavior for namespaces without a behavior annotation: v1 or v2.")
+	ownerTransforms := fs.String("owner-transforms", "", "JSON list of transforms applied to the appOwner value.")
+	nameNormalization := fs.String("name-normalization", "", "Comma separated normalization applied when matching: lowercase, alphanumeric.")
+	aliasesAnnotation := fs.String("aliases-annotation", controllers.DefaultAliasesAnnotation, "Project annotation listing alternate names.")
+	projectResolverURL := fs.String("project-resolver-url", "", "URL of an external service mapping appOwner values to project IDs.")
+	_ = fs.Parse(args)
+
+	var pattern *regexp.Regexp
+	if *ownerPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(*ownerPattern); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --owner-pattern: %v\n", err)
+			return 2
+		}
+	}
+	behavior, err := controllers.ParseBehavior(*defaultBehavior)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --default-behavior: %v\n", err)
+		return 2
+	}
+	transforms, err := controllers.ParseOwnerTransforms(*ownerTransforms)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --owner-transforms: %v\n", err)
+		return 2
+	}
+	normalizer, err := controllers.ParseNameNormalizer(*nameNormalization)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --name-normalization: %v\n", err)
+		return 2
+	}
+
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create client: %v\n", err)
+		return 1
+	}
+
+	reconciler := &controllers.NamespaceReconciler{
+		Client:            k8sClient,
+		Scheme:            scheme,
+		DefaultBehavior:   behavior,
+		OwnerTransforms:   transforms,
+		NameNormalizer:    normalizer,
+		AliasesAnnotation: *aliasesAnnotation,
+	}
+	if *projectResolverURL != "" {
+		reconciler.ProjectResolver = controllers.NewHTTPProjectResolver(*projectResolverURL)
+	}
+
+	findings, err := reconciler.Lint(context.Background(), pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lint failed: %v\n", err)
+		return 1
+	}
+
+	switch *output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to write report: %v\n", err)
+			return 1
+		}
+	default:
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAMESPACE\tOWNER\tPROBLEM\tDETAIL")
+		for _, finding := range findings {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", finding.Namespace, finding.Owner, finding.Problem, finding.Detail)
+		}
+		writer.Flush()
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
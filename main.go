@@ -1,16 +1,30 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"net/http"
 	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/quiknode-labs/qn-rancher-operator/controllers"
 	//+kubebuilder:scaffold:imports
@@ -28,14 +42,345 @@ func init() {
 }
 
 func main() {
+	// Subcommands run instead of the manager
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var resolutionAnnotation string
+	var ambiguityPolicy string
+	var ownerTransforms string
+	var decisionWebhookURL string
+	var decisionWebhookAuth string
+	var decisionWebhookAuthFile string
+	var projectResolverURL string
+	var defaultBehavior string
+	var enableWebhook bool
+	var nameNormalization string
+	var protectedProjects string
+	var inheritParentOwner bool
+	var downstreamQPS float64
+	var downstreamBurst int
+	var ownerAnnotation string
+	var ownerTemplate string
+	var historyLimit int
+	var conflictPolicy string
+	var projectCacheTTL time.Duration
+	var namespaceAllowlist string
+	var digestInterval time.Duration
+	var aliasesAnnotation string
+	var singleCluster string
+	var dryRunPatches bool
+	var planOnly bool
+	var maintenanceWindows string
+	var fallbackClusters string
+	var annotateConfidence bool
+	var clusterSource string
+	var latencyThreshold time.Duration
+	var ownerConfigMap string
+	var ownerConfigMapKey string
+	var localClusterID string
+	var statusConfigMap string
+	var statusNamespace string
+	var notReadyGracePeriod time.Duration
+	var useDisplayNameOwner bool
+	var writeTargets string
+	var carryForwardAnnotations string
+	var projectCacheRebuildInterval time.Duration
+	var clusterSelector string
+	var priorityClassLabel string
+	var resolutionProfiles string
+	var projectRequiredLabel string
+	var maxProjectList int
+	var projectResolverCEL string
+	var verifyInterval time.Duration
+	var verifyFix bool
+	var backfillClusterID bool
+	var rancherAPIURL string
+	var rancherAPIToken string
+	var rancherAPITokenFile string
+	var resolveOwnerApps bool
+	var maxClusterClients int
+	var assignmentChecksum bool
+	var verifyPatches bool
+	var projectNameTemplate string
+	var eventTypes string
+	var quarantineThreshold int
+	var globalProjectNames bool
+	var once bool
+	var pushgatewayURL string
+	var pushgatewayJob string
+	var namespaceSelectorAnnotation string
+	var ownerLabels string
+	var ownerLabelPolicy string
+	var decisionTraceLimit int
+	var serviceAccountAnnotation string
+	var serviceAccountProjects string
+	var clusterListRetries int
+	var clusterListRetryBackoff time.Duration
+	var annotateResolutionSource bool
+	var clusterClientWorkers int
+	var clusterRefreshUsername string
+	var clusterRefreshPassword string
+	var clusterRefreshPasswordFile string
+	var clusterRefreshDebounce time.Duration
+	var requeueIneligible bool
+	var projectPool string
+	var annotatePending bool
+	var fleetOwnerLabel string
+	var downstreamTokenSecret string
+	var downstreamTokenKey string
+	var namespaceDebugEndpoint bool
+	var ownerMappings bool
+	var manageProjectRBAC bool
+	var rbacRoleTemplate string
+	var rbacGroupPrincipalPrefix string
+	var splitPatches bool
+	var costCenterLabel string
+	var costCenterConfigMap string
+	var output string
+	var upgradingCondition string
+	var namespaceQPS float64
+	var namespaceBurst int
+	var labelProjectName bool
+	var failOnHookError bool
+	var maxConcurrentPatches int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&resolutionAnnotation, "resolution-annotation", "",
+		"Annotation key used to record the project resolution as JSON, e.g. "+controllers.DefaultResolutionAnnotation+". "+
+			"Disabled when empty. Enabling it rewrites every assigned namespace once to add the annotation.")
+	flag.StringVar(&ambiguityPolicy, "ambiguity-policy", string(controllers.AmbiguityPolicyFirst),
+		"How to pick a project when several match the appOwner value: fail, first, annotation, weighted, or recency.")
+	flag.StringVar(&ownerTransforms, "owner-transforms", "",
+		"JSON list of transforms applied to the appOwner value, e.g. "+
+			`[{"type":"trim"},{"type":"lowercase"},{"type":"stripPrefix","prefix":"team-"}]`)
+	flag.StringVar(&decisionWebhookURL, "decision-webhook-url", "",
+		"URL that receives a JSON record of every reconcile decision. Disabled when empty.")
+	secretStringVar(&decisionWebhookAuth, "decision-webhook-auth", "",
+		"Value of the Authorization header sent to the decision webhook. Visible in the process list, prefer --decision-webhook-auth-file.")
+	flag.StringVar(&decisionWebhookAuthFile, "decision-webhook-auth-file", "",
+		"File holding the value of the Authorization header sent to the decision webhook, e.g. a mounted Secret key.")
+	flag.StringVar(&projectResolverURL, "project-resolver-url", "",
+		"URL of an external service mapping appOwner values to project IDs. Disabled when empty.")
+	flag.StringVar(&defaultBehavior, "default-behavior", "",
+		"Resolution behavior for namespaces without the rancher-operator.quiknode.io/behavior annotation: v1 or v2. "+
+			"Defaults to v2 when --owner-transforms or a project resolver is configured, v1 otherwise.")
+	flag.BoolVar(&enableWebhook, "enable-webhook", false,
+		"Serve the namespace validating webhook that warns about terminating projects on port 9443. "+
+			"Requires the webhook configuration and serving certificate from config/webhook or the chart's webhook.enabled.")
+	flag.StringVar(&nameNormalization, "name-normalization", "",
+		"Comma separated normalization applied when matching owners to project names: lowercase, alphanumeric, diacritics.")
+	flag.StringVar(&protectedProjects, "protected-projects", strings.Join(controllers.DefaultProtectedProjects, ","),
+		"Comma separated project names that namespaces are never assigned to without an explicit override.")
+	flag.BoolVar(&inheritParentOwner, "inherit-parent-owner", false,
+		"Use the appOwner label of the HNC parent namespace when a subnamespace has none.")
+	flag.Float64Var(&downstreamQPS, "downstream-qps", 0,
+		"Maximum requests per second sent to each downstream cluster. Zero uses client-go defaults.")
+	flag.IntVar(&downstreamBurst, "downstream-burst", 10,
+		"Burst allowed above --downstream-qps for each downstream cluster.")
+	flag.StringVar(&ownerAnnotation, "owner-annotation", "",
+		"Annotation read for the owner when the appOwner label is missing. Disabled when empty.")
+	flag.StringVar(&ownerTemplate, "owner-template", "",
+		"Go template applied to the owner annotation value, e.g. '{{ .Value }}'. Fields: .Value, .Namespace.")
+	flag.IntVar(&historyLimit, "history-limit", 0,
+		"Number of past project assignments kept in the namespace history annotation. Zero disables it.")
+	flag.StringVar(&conflictPolicy, "conflict-policy", string(controllers.ConflictPolicyPreferOwner),
+		"What to do when a namespace is assigned to a different project than its owner: prefer-owner, keep-existing, or event-and-skip.")
+	flag.DurationVar(&projectCacheTTL, "project-cache-ttl", 0,
+		"How long the Rancher Project list is cached and warmed at startup. Zero disables the cache.")
+	flag.StringVar(&namespaceAllowlist, "namespace-allowlist", "",
+		"Regular expression a namespace name must match to be assigned. All namespaces are eligible when empty.")
+	flag.DurationVar(&digestInterval, "digest-interval", 0,
+		"Interval at which a summary of assignment activity is logged, e.g. 24h. Disabled when zero.")
+	flag.StringVar(&aliasesAnnotation, "aliases-annotation", controllers.DefaultAliasesAnnotation,
+		"Project annotation listing alternate names (comma separated or JSON list). Set to empty to disable.")
+	flag.StringVar(&singleCluster, "single-cluster", "",
+		"Only manage the given cluster ID, disabling cluster discovery. All clusters are managed when empty.")
+	flag.BoolVar(&dryRunPatches, "dry-run-patches", false,
+		"Validate namespace patches with a server-side dry run and skip namespaces that reject them.")
+	flag.BoolVar(&planOnly, "plan-only", false,
+		"Emit the labels and annotations each namespace would receive as a DryRunDiff event without patching it.")
+	flag.StringVar(&maintenanceWindows, "maintenance-windows", "",
+		"Comma separated daily UTC windows (HH:MM-HH:MM) during which namespaces may be patched. Always allowed when empty.")
+	flag.StringVar(&fallbackClusters, "fallback-clusters", "",
+		"Comma separated cluster IDs searched in order when a project is not found in the namespace's cluster.")
+	flag.BoolVar(&annotateConfidence, "annotate-confidence", false,
+		"Annotate namespaces with the confidence (exact, high, low) of the project match.")
+	flag.StringVar(&clusterSource, "cluster-source", string(controllers.ClusterSourceRancher),
+		"Where downstream clusters are discovered from: rancher or capi.")
+	flag.DurationVar(&latencyThreshold, "latency-threshold", 0,
+		"Rolling project List latency above which reconciles are delayed. Disabled when zero.")
+	flag.StringVar(&ownerConfigMap, "owner-configmap", "",
+		"Name of a ConfigMap in each namespace read for the owner when the appOwner label is missing.")
+	flag.StringVar(&ownerConfigMapKey, "owner-configmap-key", "owner",
+		"Key of the owner ConfigMap holding the owner value.")
+	flag.StringVar(&localClusterID, "local-cluster-id", "",
+		"Canonical cluster ID written to the clusterId label instead of \"local\". Keeps \"local\" when empty.")
+	flag.StringVar(&statusConfigMap, "status-configmap", "",
+		"Name of a ConfigMap holding Assigned and Reconciled conditions of every namespace, keyed <clusterId>.<namespace> "+
+			"and written in batches. Postponed reconciles are not recorded. Disabled when empty.")
+	flag.StringVar(&statusNamespace, "status-namespace", "qn-rancher-operator-system",
+		"Namespace of the status ConfigMap.")
+	flag.DurationVar(&notReadyGracePeriod, "not-ready-grace-period", 0,
+		"How long a cluster may be not ready before its client is dropped.")
+	flag.BoolVar(&useDisplayNameOwner, "use-display-name-owner", false,
+		"Use the Rancher field.cattle.io/displayName namespace annotation as the owner when no other owner is set.")
+	flag.StringVar(&writeTargets, "write-targets", string(controllers.WriteBoth),
+		"Which project metadata is written to namespaces: both, labels, or annotations.")
+	flag.StringVar(&carryForwardAnnotations, "carry-forward-annotations", strings.Join(controllers.DefaultCarryForwardAnnotations, ","),
+		"Comma separated annotations copied from the namespace named in rancher-operator.quiknode.io/predecessor.")
+	flag.DurationVar(&projectCacheRebuildInterval, "project-cache-rebuild-interval", 0,
+		"Interval at which the project cache is rebuilt in full. Disabled when zero.")
+	flag.StringVar(&clusterSelector, "cluster-selector", "",
+		"Label selector on Cluster objects limiting which clusters get a client, e.g. autoassign!=disabled.")
+	flag.StringVar(&priorityClassLabel, "priority-class-label", "",
+		"Namespace label stamped with the PriorityClass from the project's "+
+			"rancher-operator.quiknode.io/priority-class annotation, e.g. "+controllers.DefaultPriorityClassLabel+". Disabled when empty.")
+	flag.StringVar(&resolutionProfiles, "resolution-profiles", "",
+		"JSON list of resolution profiles evaluated in order until one resolves, e.g. "+
+			`[{"name":"team","ownerLabel":"team","matchMode":"displayName","projectSelector":"tier=prod"}]. `+
+			"Match modes are any, displayName and id. Replaces the appOwner lookup when set.")
+	flag.StringVar(&projectRequiredLabel, "project-required-label", "",
+		"Label selector a project must match to be eligible for assignment, e.g. managed=true. "+
+			"Projects that later gain the label requeue waiting namespaces. Disabled when empty.")
+	flag.IntVar(&maxProjectList, "max-project-list", 0,
+		"Maximum number of projects loaded, page by page, by an uncached project lookup. "+
+			"Lookups that do not find the project within the cap fail. Unlimited when 0.")
+	flag.StringVar(&projectResolverCEL, "project-resolver-cel", "",
+		"CEL expression over name, labels, annotations, owner and clusterId returning the target project name "+
+			"for namespaces using the v2 behavior, e.g. labels['team'] + '-' + labels['env']. Namespaces without an owner are evaluated too. "+
+			"Only one project resolver can be configured.")
+	flag.DurationVar(&verifyInterval, "verify-interval", 0,
+		"Interval of a verification pass reporting namespaces assigned to missing or ineligible projects. Disabled when 0.")
+	flag.BoolVar(&verifyFix, "verify-fix", false,
+		"Clear the projectId of dangling assignments found by the verification pass so they are resolved again.")
+	flag.BoolVar(&backfillClusterID, "backfill-cluster-id", false,
+		"Add the missing clusterId label to namespaces that only carry a projectId, without re-resolving the project.")
+	flag.StringVar(&rancherAPIURL, "rancher-api-url", "",
+		"URL of the Rancher server whose v3 REST API lists the projects namespaces are matched against, "+
+			"instead of the Project objects on the management cluster.")
+	secretStringVar(&rancherAPIToken, "rancher-api-token", "",
+		"Bearer token used to authenticate to the Rancher v3 API. Visible in the process list, prefer --rancher-api-token-file.")
+	flag.StringVar(&rancherAPITokenFile, "rancher-api-token-file", "",
+		"File holding the bearer token used to authenticate to the Rancher v3 API, e.g. a mounted Secret key.")
+	flag.BoolVar(&resolveOwnerApps, "resolve-owner-apps", false,
+		"Assign namespaces owned by a Rancher App to the app's project.")
+	flag.IntVar(&maxClusterClients, "max-cluster-clients", 0,
+		"Maximum number of downstream cluster clients held, evicting the least recently used. Unlimited when 0.")
+	flag.BoolVar(&assignmentChecksum, "assignment-checksum", false,
+		"Stamp a checksum of the assignment inputs on namespaces and skip namespaces whose checksum is unchanged.")
+	flag.BoolVar(&verifyPatches, "verify-patches", false,
+		"Read namespaces back after patching and requeue with a Warning event when the assignment did not persist.")
+	flag.StringVar(&projectNameTemplate, "project-name-template", "",
+		"Go template deriving the project name from the owner, e.g. \"{{ .Owner }}-apps\". "+
+			"Fields are .Owner, .Namespace and .ClusterID. The owner is used as is when empty.")
+	flag.StringVar(&eventTypes, "event-types", "",
+		"Comma separated reason=type pairs overriding the type of emitted events, "+
+			"e.g. ProjectNotFound=Warning,DriftCorrected=Normal.")
+	flag.IntVar(&quarantineThreshold, "quarantine-threshold", 0,
+		"Number of consecutive failed patches after which a namespace is annotated with "+
+			"rancher-operator.quiknode.io/quarantined and skipped until the annotation is removed. Disabled when 0.")
+	flag.BoolVar(&globalProjectNames, "global-project-names", false,
+		"Assume project names are unique across clusters: search every cluster and assign the single match, "+
+			"failing when several projects share the name.")
+	flag.BoolVar(&once, "once", false,
+		"Reconcile every namespace a single time and exit instead of running the controller.")
+	flag.StringVar(&pushgatewayURL, "pushgateway-url", "",
+		"URL of a Prometheus Pushgateway the metrics of a --once run are pushed to before exiting. Disabled when empty.")
+	flag.StringVar(&pushgatewayJob, "pushgateway-job", "qn-rancher-operator",
+		"Job name used when pushing metrics to the Pushgateway.")
+	flag.StringVar(&namespaceSelectorAnnotation, "namespace-selector-annotation", "",
+		"Project annotation holding a label selector for its namespaces, e.g. "+controllers.DefaultNamespaceSelectorAnnotation+". "+
+			"When set, namespaces using the v2 behavior, with or without an owner, are assigned to the project whose selector matches their labels. "+
+			"Only one project resolver can be configured.")
+	flag.StringVar(&ownerLabels, "owner-labels", "",
+		"Comma separated namespace label keys holding the owner, in order. Only appOwner is read when empty.")
+	flag.StringVar(&ownerLabelPolicy, "owner-label-policy", string(controllers.OwnerLabelPolicyFirstWins),
+		"What to do when owner labels disagree: first-wins, error, or require-agreement.")
+	flag.IntVar(&decisionTraceLimit, "decision-trace-limit", 0,
+		"Number of recent decisions across all namespaces kept in an annotation on the status ConfigMap, at most 1000. "+
+			"The oldest decisions are also dropped once the trace exceeds 128 KiB. Requires --status-configmap. Disabled when 0.")
+	flag.StringVar(&serviceAccountAnnotation, "service-account-annotation", "",
+		"Namespace annotation holding the service account that created the namespace. "+
+			"Mapped to a project through --service-account-projects before any owner label is consulted.")
+	flag.StringVar(&serviceAccountProjects, "service-account-projects", "",
+		"Comma separated serviceAccount=project pairs used with --service-account-annotation, "+
+			"for example system:serviceaccount:ci:deployer=platform.")
+	flag.IntVar(&clusterListRetries, "cluster-list-retries", 3,
+		"Number of times a failed cluster List is retried within one cluster refresh.")
+	flag.DurationVar(&clusterListRetryBackoff, "cluster-list-retry-backoff", time.Second,
+		"Delay before the first cluster List retry, doubled after each retry.")
+	flag.BoolVar(&annotateResolutionSource, "annotate-resolution-source", false,
+		"Record which source produced the project match (label, annotation, configmap, resolver, template, ...) "+
+			"in the rancher-operator.quiknode.io/resolution-source annotation.")
+	flag.IntVar(&clusterClientWorkers, "cluster-client-workers", 8,
+		"Number of downstream cluster clients created in parallel during a cluster refresh.")
+	flag.StringVar(&clusterRefreshUsername, "cluster-refresh-username", "",
+		"Basic auth username for the cluster refresh webhook served on the metrics server at "+
+			controllers.ClusterRefreshWebhookPath+". The webhook is disabled unless a username and password are set.")
+	secretStringVar(&clusterRefreshPassword, "cluster-refresh-password", "",
+		"Basic auth password for the cluster refresh webhook. Visible in the process list, prefer --cluster-refresh-password-file.")
+	flag.StringVar(&clusterRefreshPasswordFile, "cluster-refresh-password-file", "",
+		"File holding the basic auth password for the cluster refresh webhook, e.g. a mounted Secret key.")
+	flag.DurationVar(&clusterRefreshDebounce, "cluster-refresh-debounce", 10*time.Second,
+		"Delay before a requested cluster refresh runs, merging notifications that arrive in the meantime.")
+	flag.BoolVar(&requeueIneligible, "requeue-ineligible-projects", true,
+		"Retry namespaces with backoff when their project exists but is not eligible or is terminating.")
+	flag.StringVar(&projectPool, "project-pool", "",
+		"Comma separated project names. When set, every namespace is assigned to one of them chosen by hashing "+
+			"the namespace name, for synthetic fleets that need an even distribution.")
+	flag.BoolVar(&annotatePending, "annotate-pending", false,
+		"Annotate namespaces whose assignment is deferred with the intended project until it is applied.")
+	flag.StringVar(&fleetOwnerLabel, "fleet-owner-label", "",
+		"Namespace label propagated from a Rancher Fleet bundle, e.g. fleet.cattle.io/bundle-name, "+
+			"used as the owner when no owner label, annotation or ConfigMap is present.")
+	flag.StringVar(&downstreamTokenSecret, "downstream-token-secret", "",
+		"Secret, as namespace/name, holding the bearer token presented to Rancher's cluster proxy. "+
+			"Required when the management config authenticates with a client certificate.")
+	flag.StringVar(&downstreamTokenKey, "downstream-token-key", controllers.DefaultDownstreamTokenKey,
+		"Key of the token in --downstream-token-secret.")
+	flag.BoolVar(&namespaceDebugEndpoint, "namespace-debug-endpoint", false,
+		"Serve the operator's view of a namespace at "+controllers.NamespaceStatePath+"{name} on the metrics server. "+
+			"Callers present a bearer token and need RBAC access to the non-resource URL.")
+	flag.BoolVar(&manageProjectRBAC, "manage-project-rbac", false,
+		"Bind the owner group to each namespace's project with a ProjectRoleTemplateBinding, moving it when the namespace is reassigned.")
+	flag.StringVar(&rbacRoleTemplate, "rbac-role-template", controllers.DefaultRBACRoleTemplate,
+		"Role template granted to the owner group when --manage-project-rbac is set.")
+	flag.StringVar(&rbacGroupPrincipalPrefix, "rbac-group-principal-prefix", "",
+		"Prefix prepended to the owner to form the group principal, e.g. keycloakoidc_group://")
+	flag.BoolVar(&ownerMappings, "owner-mappings", false,
+		"Assign namespaces using OwnerMapping resources before any other resolution source. "+
+			"Requires the OwnerMapping CRD from config/crd.")
+	flag.BoolVar(&splitPatches, "split-patches", false,
+		"Patch the project ID label and annotation alone first, then the supplementary labels and annotations "+
+			"best effort, so admission rejecting a supplementary key does not block the assignment.")
+	flag.StringVar(&costCenterLabel, "cost-center-label", "",
+		"Namespace label holding a cost center that is mapped to a project through --cost-center-configmap.")
+	flag.StringVar(&costCenterConfigMap, "cost-center-configmap", "",
+		"ConfigMap, as namespace/name, whose data maps cost centers to project names.")
+	flag.StringVar(&output, "output", outputTable,
+		"Format of the per-namespace summary printed to stdout after a --once run: table or json.")
+	flag.StringVar(&upgradingCondition, "cluster-upgrading-condition", "",
+		"Rancher Cluster condition type, e.g. Updated, whose Unknown status marks an upgrade in progress. "+
+			"Assignments on upgrading clusters are deferred. Disabled when empty.")
+	flag.Float64Var(&namespaceQPS, "namespace-qps", 0,
+		"Maximum reconciles per second of a single namespace; excess reconciles are requeued. Unlimited when 0.")
+	flag.IntVar(&namespaceBurst, "namespace-burst", 5,
+		"Burst of reconciles allowed for a single namespace when --namespace-qps is set.")
+	flag.BoolVar(&labelProjectName, "label-project-name", false,
+		"Label namespaces with the resolved project's display name, sanitized to a valid label value, "+
+			"in rancher-operator.quiknode.io/project-name.")
+	flag.BoolVar(&failOnHookError, "fail-on-assignment-hook-error", false,
+		"Fail the reconcile when the post-assignment hook returns an error instead of only logging it.")
+	flag.IntVar(&maxConcurrentPatches, "max-concurrent-patches", 0,
+		"Maximum namespace patches in flight against a single cluster. Unlimited when 0.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -44,9 +389,207 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
+	for _, secret := range []struct {
+		flag  string
+		value *string
+		file  string
+	}{
+		{"decision-webhook-auth", &decisionWebhookAuth, decisionWebhookAuthFile},
+		{"rancher-api-token", &rancherAPIToken, rancherAPITokenFile},
+		{"cluster-refresh-password", &clusterRefreshPassword, clusterRefreshPasswordFile},
+	} {
+		value, err := secretValue(secret.flag, *secret.value, secret.file)
+		if err != nil {
+			setupLog.Error(err, "invalid flag value", "flag", secret.flag+"-file")
+			os.Exit(1)
+		}
+		*secret.value = value
+	}
+
+	policy, err := controllers.ParseAmbiguityPolicy(ambiguityPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "ambiguity-policy")
+		os.Exit(1)
+	}
+
+	transforms, err := controllers.ParseOwnerTransforms(ownerTransforms)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "owner-transforms")
+		os.Exit(1)
+	}
+
+	// Owner transforms and project resolvers only apply to v2, so configuring
+	// them without choosing a behavior opts every namespace into v2
+	var v2Flags []string
+	for name, value := range map[string]string{
+		"owner-transforms":              ownerTransforms,
+		"project-resolver-url":          projectResolverURL,
+		"project-resolver-cel":          projectResolverCEL,
+		"namespace-selector-annotation": namespaceSelectorAnnotation,
+	} {
+		if value != "" {
+			v2Flags = append(v2Flags, "--"+name)
+		}
+	}
+	sort.Strings(v2Flags)
+	if defaultBehavior == "" {
+		defaultBehavior = string(controllers.BehaviorV1)
+		if len(v2Flags) > 0 {
+			defaultBehavior = string(controllers.BehaviorV2)
+			setupLog.Info("defaulting to the v2 behavior for v2-only flags", "flags", v2Flags)
+		}
+	} else if defaultBehavior == string(controllers.BehaviorV1) && len(v2Flags) > 0 {
+		setupLog.Info("v2-only flags apply only to namespaces annotated with the v2 behavior", "flags", v2Flags)
+	}
+	behavior, err := controllers.ParseBehavior(defaultBehavior)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "default-behavior")
+		os.Exit(1)
+	}
+
+	normalizer, err := controllers.ParseNameNormalizer(nameNormalization)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "name-normalization")
+		os.Exit(1)
+	}
+
+	ownerTmpl, err := controllers.ParseOwnerTemplate(ownerTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "owner-template")
+		os.Exit(1)
+	}
+
+	conflict, err := controllers.ParseConflictPolicy(conflictPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "conflict-policy")
+		os.Exit(1)
+	}
+
+	var allowlist *regexp.Regexp
+	if namespaceAllowlist != "" {
+		if allowlist, err = regexp.Compile(namespaceAllowlist); err != nil {
+			setupLog.Error(err, "invalid flag value", "flag", "namespace-allowlist")
+			os.Exit(1)
+		}
+	}
+
+	windows, err := controllers.ParseMaintenanceWindows(maintenanceWindows)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "maintenance-windows")
+		os.Exit(1)
+	}
+
+	source, err := controllers.ParseClusterSource(clusterSource)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "cluster-source")
+		os.Exit(1)
+	}
+
+	targets, err := controllers.ParseWriteTargets(writeTargets)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "write-targets")
+		os.Exit(1)
+	}
+
+	selector, err := labels.Parse(clusterSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "cluster-selector")
+		os.Exit(1)
+	}
+
+	profiles, err := controllers.ParseResolutionProfiles(resolutionProfiles)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "resolution-profiles")
+		os.Exit(1)
+	}
+
+	var requiredLabel labels.Selector
+	if projectRequiredLabel != "" {
+		if requiredLabel, err = labels.Parse(projectRequiredLabel); err != nil {
+			setupLog.Error(err, "invalid flag value", "flag", "project-required-label")
+			os.Exit(1)
+		}
+	}
+
+	projectNameTmpl, err := controllers.ParseProjectNameTemplate(projectNameTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "project-name-template")
+		os.Exit(1)
+	}
+
+	eventTypeMapping, err := controllers.ParseEventTypes(eventTypes)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "event-types")
+		os.Exit(1)
+	}
+
+	labelPolicy, err := controllers.ParseOwnerLabelPolicy(ownerLabelPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "owner-label-policy")
+		os.Exit(1)
+	}
+
+	serviceAccountMapping, err := controllers.ParseServiceAccountProjects(serviceAccountProjects)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "service-account-projects")
+		os.Exit(1)
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	// Debug endpoints on the metrics server authenticate callers against the API server
+	reviewClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create review client")
+		os.Exit(1)
+	}
+
+	extraHandlers := map[string]http.Handler{
+		// Exemplars are only exposed in the OpenMetrics format
+		"/metrics/openmetrics": promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+		// The health probe server cannot be extended, so the configuration is served next to the metrics
+		configPath: &controllers.AuthorizingHandler{Client: reviewClient, Next: configHandler(flag.CommandLine)},
+	}
+	var refreshWebhook *controllers.ClusterRefreshWebhook
+	if clusterRefreshUsername != "" && clusterRefreshPassword != "" {
+		refreshWebhook = controllers.NewClusterRefreshWebhook(clusterRefreshUsername, clusterRefreshPassword)
+		extraHandlers[controllers.ClusterRefreshWebhookPath] = refreshWebhook
+	}
+
+	tokenSecret, err := controllers.ParseNamespacedName(downstreamTokenSecret)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "downstream-token-secret")
+		os.Exit(1)
+	}
+
+	// The reconciler is attached once it is built, before the metrics server starts
+	namespaceState := &controllers.NamespaceStateHandler{}
+	if namespaceDebugEndpoint {
+		extraHandlers[controllers.NamespaceStatePath] = &controllers.AuthorizingHandler{Client: reviewClient, Next: namespaceState}
+	}
+
+	costCenterMapping, err := controllers.ParseNamespacedName(costCenterConfigMap)
+	if err != nil {
+		setupLog.Error(err, "invalid flag value", "flag", "cost-center-configmap")
+		os.Exit(1)
+	}
+
+	if output != outputTable && output != outputJSON {
+		setupLog.Error(errors.New("unknown output format "+output+", expected table or json"), "invalid flag value", "flag", "output")
+		os.Exit(1)
+	}
+
+	if decisionTraceLimit > controllers.MaxDecisionTraceLimit {
+		setupLog.Error(errors.New("decision trace limit is above the maximum"), "invalid flag value",
+			"flag", "decision-trace-limit", "max", controllers.MaxDecisionTraceLimit)
+		os.Exit(1)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress:   metricsAddr,
+			ExtraHandlers: extraHandlers,
+		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "qn-rancher-operator-lock",
@@ -56,13 +599,163 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.NamespaceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	var decisionSink controllers.DecisionSink
+	if decisionWebhookURL != "" {
+		webhookSink := controllers.NewWebhookDecisionSink(decisionWebhookURL, decisionWebhookAuth, 0)
+		if err := mgr.Add(webhookSink); err != nil {
+			setupLog.Error(err, "unable to set up decision webhook")
+			os.Exit(1)
+		}
+		decisionSink = webhookSink
+	}
+
+	var projectResolver controllers.ProjectResolver
+	configuredResolvers := 0
+	for _, value := range []string{projectResolverURL, projectResolverCEL, namespaceSelectorAnnotation} {
+		if value != "" {
+			configuredResolvers++
+		}
+	}
+	switch {
+	case configuredResolvers > 1:
+		setupLog.Error(errors.New("--project-resolver-url, --project-resolver-cel and --namespace-selector-annotation are mutually exclusive"), "invalid flag value", "flag", "project-resolver-url")
+		os.Exit(1)
+	case projectResolverURL != "":
+		projectResolver = controllers.NewHTTPProjectResolver(projectResolverURL)
+	case projectResolverCEL != "":
+		celResolver, err := controllers.NewCELProjectResolver(projectResolverCEL)
+		if err != nil {
+			setupLog.Error(err, "invalid flag value", "flag", "project-resolver-cel")
+			os.Exit(1)
+		}
+		projectResolver = celResolver
+	}
+
+	reconciler := &controllers.NamespaceReconciler{
+		Client:                      mgr.GetClient(),
+		Scheme:                      mgr.GetScheme(),
+		ResolutionAnnotation:        resolutionAnnotation,
+		AmbiguityPolicy:             policy,
+		OwnerTransforms:             transforms,
+		DecisionSink:                decisionSink,
+		ProjectResolver:             projectResolver,
+		DefaultBehavior:             behavior,
+		Recorder:                    mgr.GetEventRecorderFor("qn-rancher-operator"),
+		NameNormalizer:              normalizer,
+		ProtectedProjects:           splitList(protectedProjects),
+		InheritParentOwner:          inheritParentOwner,
+		DownstreamQPS:               float32(downstreamQPS),
+		DownstreamBurst:             downstreamBurst,
+		OwnerAnnotation:             ownerAnnotation,
+		OwnerTemplate:               ownerTmpl,
+		HistoryLimit:                historyLimit,
+		ConflictPolicy:              conflict,
+		ProjectCacheTTL:             projectCacheTTL,
+		NamespaceAllowlist:          allowlist,
+		AliasesAnnotation:           aliasesAnnotation,
+		SingleCluster:               singleCluster,
+		DryRunPatches:               dryRunPatches,
+		PlanOnly:                    planOnly,
+		MaintenanceWindows:          windows,
+		FallbackClusters:            splitList(fallbackClusters),
+		AnnotateConfidence:          annotateConfidence,
+		ClusterSource:               source,
+		LatencyThreshold:            latencyThreshold,
+		OwnerConfigMap:              ownerConfigMap,
+		OwnerConfigMapKey:           ownerConfigMapKey,
+		LocalClusterID:              localClusterID,
+		StatusConfigMap:             statusConfigMap,
+		StatusNamespace:             statusNamespace,
+		NotReadyGracePeriod:         notReadyGracePeriod,
+		UseDisplayNameOwner:         useDisplayNameOwner,
+		WriteTargets:                targets,
+		CarryForwardAnnotations:     splitList(carryForwardAnnotations),
+		ProjectCacheRebuildInterval: projectCacheRebuildInterval,
+		ClusterSelector:             selector,
+		PriorityClassLabel:          priorityClassLabel,
+		ResolutionProfiles:          profiles,
+		ProjectRequiredLabel:        requiredLabel,
+		MaxProjectList:              maxProjectList,
+		BackfillClusterID:           backfillClusterID,
+		ResolveOwnerApps:            resolveOwnerApps,
+		MaxClusterClients:           maxClusterClients,
+		AssignmentChecksum:          assignmentChecksum,
+		VerifyPatches:               verifyPatches,
+		ProjectNameTemplate:         projectNameTmpl,
+		EventTypes:                  eventTypeMapping,
+		QuarantineThreshold:         quarantineThreshold,
+		GlobalProjectNames:          globalProjectNames,
+		Once:                        once,
+		OwnerLabels:                 splitList(ownerLabels),
+		OwnerLabelPolicy:            labelPolicy,
+		DecisionTraceLimit:          decisionTraceLimit,
+		ServiceAccountAnnotation:    serviceAccountAnnotation,
+		ServiceAccountProjects:      serviceAccountMapping,
+		ClusterListRetries:          clusterListRetries,
+		ClusterListRetryBackoff:     clusterListRetryBackoff,
+		AnnotateResolutionSource:    annotateResolutionSource,
+		ClusterClientWorkers:        clusterClientWorkers,
+		ClusterRefreshDebounce:      clusterRefreshDebounce,
+		RequeueIneligible:           requeueIneligible,
+		ProjectPool:                 splitList(projectPool),
+		AnnotatePending:             annotatePending,
+		FleetOwnerLabel:             fleetOwnerLabel,
+		DownstreamTokenSecret:       tokenSecret,
+		DownstreamTokenKey:          downstreamTokenKey,
+		OwnerMappings:               ownerMappings,
+		ManageProjectRBAC:           manageProjectRBAC,
+		RBACRoleTemplate:            rbacRoleTemplate,
+		RBACGroupPrincipalPrefix:    rbacGroupPrincipalPrefix,
+		SplitPatches:                splitPatches,
+		CostCenterLabel:             costCenterLabel,
+		CostCenterConfigMap:         costCenterMapping,
+		UpgradingCondition:          upgradingCondition,
+		NamespaceQPS:                float32(namespaceQPS),
+		NamespaceBurst:              namespaceBurst,
+		LabelProjectName:            labelProjectName,
+		FailOnHookError:             failOnHookError,
+		MaxConcurrentPatches:        maxConcurrentPatches,
+	}
+	if namespaceSelectorAnnotation != "" {
+		// The selector resolver reads projects through the reconciler's project cache
+		reconciler.ProjectResolver = &controllers.NamespaceSelectorResolver{Reconciler: reconciler, Annotation: namespaceSelectorAnnotation}
+	}
+	if rancherAPIURL != "" {
+		reconciler.ProjectLister = controllers.NewRancherAPIProjectLister(rancherAPIURL, rancherAPIToken)
+	}
+	if refreshWebhook != nil {
+		reconciler.ClusterRefreshRequests = refreshWebhook.Requests()
+	}
+	namespaceState.Reconciler = reconciler
+	if digestInterval > 0 {
+		reconciler.Digest = controllers.NewActivityDigest(digestInterval, reconciler.Clock)
+		if err := mgr.Add(reconciler.Digest); err != nil {
+			setupLog.Error(err, "unable to set up activity digest")
+			os.Exit(1)
+		}
+	}
+
+	if verifyInterval > 0 {
+		verifier := &controllers.AssignmentVerifier{Reconciler: reconciler, Interval: verifyInterval, Fix: verifyFix}
+		if err := mgr.Add(verifier); err != nil {
+			setupLog.Error(err, "unable to set up assignment verification")
+			os.Exit(1)
+		}
+	}
+
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Namespace")
 		os.Exit(1)
 	}
+
+	if enableWebhook {
+		mgr.GetWebhookServer().Register(controllers.NamespaceWebhookPath, &webhook.Admission{
+			Handler: &controllers.NamespaceValidator{
+				Reconciler: reconciler,
+				Decoder:    admission.NewDecoder(mgr.GetScheme()),
+			},
+		})
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -74,9 +767,48 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+	var onceRunner *controllers.OnceRunner
+	if once {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		onceRunner = &controllers.OnceRunner{Reconciler: reconciler, Stop: cancel}
+		if err := mgr.Add(onceRunner); err != nil {
+			setupLog.Error(err, "unable to set up single pass")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if once {
+		if err := writeOnceSummary(os.Stdout, output, onceRunner.Decisions); err != nil {
+			setupLog.Error(err, "unable to write summary")
+			os.Exit(1)
+		}
+	}
+
+	// In-process metrics of a single pass disappear on exit, so hand them to the Pushgateway
+	if once && pushgatewayURL != "" {
+		if err := push.New(pushgatewayURL, pushgatewayJob).Gatherer(metrics.Registry).Push(); err != nil {
+			setupLog.Error(err, "unable to push metrics to the Pushgateway", "url", pushgatewayURL)
+			os.Exit(1)
+		}
+		setupLog.Info("pushed metrics to the Pushgateway", "url", pushgatewayURL, "job", pushgatewayJob)
+	}
+}
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
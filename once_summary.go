@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/quiknode-labs/qn-rancher-operator/controllers"
+)
+
+// Summary formats of a --once run
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// writeOnceSummary reports the per-namespace outcome of a single pass,
+// sorted by namespace
+func writeOnceSummary(w io.Writer, format string, decisions []controllers.Decision) error {
+	sorted := append([]controllers.Decision(nil), decisions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Namespace < sorted[j].Namespace
+	})
+
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(sorted)
+	default:
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAMESPACE\tOWNER\tPROJECT\tOUTCOME\tREASON")
+		for _, decision := range sorted {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+				decision.Namespace, decision.Owner, decision.ProjectID, decision.Outcome, decision.Reason)
+		}
+		return writer.Flush()
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/quiknode-labs/qn-rancher-operator/controllers"
+)
+
+var onceDecisions = []controllers.Decision{
+	{Namespace: "payments", Owner: "payments", ProjectID: "p-live", Outcome: controllers.DecisionAssigned, Reason: controllers.ReasonAssigned},
+	{Namespace: "kube-system", Outcome: controllers.DecisionSkipped, Reason: controllers.ReasonNoOwnerLabel},
+	{Namespace: "orders", Owner: "orders", Outcome: controllers.DecisionError, Reason: controllers.ReasonProjectNotFound},
+}
+
+func TestWriteOnceSummaryTable(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeOnceSummary(&out, outputTable, onceDecisions); err != nil {
+		t.Fatalf("writeOnceSummary() error = %v", err)
+	}
+
+	want := "NAMESPACE    OWNER     PROJECT  OUTCOME   REASON\n" +
+		"kube-system                     Skipped   " + string(controllers.ReasonNoOwnerLabel) + "\n" +
+		"orders       orders             Error     " + string(controllers.ReasonProjectNotFound) + "\n" +
+		"payments     payments  p-live   Assigned  " + string(controllers.ReasonAssigned) + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("writeOnceSummary() table =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteOnceSummaryJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeOnceSummary(&out, outputJSON, onceDecisions); err != nil {
+		t.Fatalf("writeOnceSummary() error = %v", err)
+	}
+
+	var decoded []controllers.Decision
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("summary is not JSON: %v", err)
+	}
+	if len(decoded) != 3 || decoded[0].Namespace != "kube-system" || decoded[2].Namespace != "payments" {
+		t.Errorf("writeOnceSummary() JSON = %+v, want decisions sorted by namespace", decoded)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// secretValue returns the secret passed directly through flagName or read
+// from the file passed through its -file variant, typically a mounted Secret.
+// Setting both is an error. Values passed on the command line are visible in
+// the process list, so the file is preferred.
+func secretValue(flagName, value, file string) (string, error) {
+	if file == "" {
+		return value, nil
+	}
+	if value != "" {
+		return "", fmt.Errorf("--%s and --%s-file are mutually exclusive", flagName, flagName)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read --%s-file: %w", flagName, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}